@@ -6,12 +6,22 @@ import (
 	"runtime/debug"
 )
 
+// ErrCancelled is returned, wrapping the context error, when waiting for a Future is given up
 var ErrCancelled = fmt.Errorf("cancelled")
 
 // Future represents a value that will be available at some point in the future
 type Future[T any] struct {
 	// await returns the value of the Future
 	await func() (T, error)
+
+	// done is closed once the value of the Future is available
+	done <-chan struct{}
+
+	// cancel cancels the context passed to the function of the Future
+	cancel context.CancelFunc
+
+	// ctx is the context the Future was created with, used for futures chained to it
+	ctx context.Context
 }
 
 // Await waits for the Future to complete and returns the result
@@ -19,14 +29,57 @@ func (f *Future[T]) Await() (T, error) {
 	return f.await()
 }
 
+// AwaitCtx waits for the Future to complete and returns the result. If ctx is done first, it
+// returns ErrCancelled wrapping the context error. A completed Future returns its result even if ctx
+// is already done. The Future keeps running and may be awaited again.
+func (f *Future[T]) AwaitCtx(ctx context.Context) (T, error) {
+	if err := f.wait(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	return f.await()
+}
+
+// wait waits for the Future to complete and returns nil, or ErrCancelled wrapping the context error
+// if ctx is done first. A completed Future is preferred over a done ctx.
+func (f *Future[T]) wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return nil
+	default:
+	}
+	select {
+	case <-f.done:
+		return nil
+	case <-ctx.Done():
+		return cancelled(ctx)
+	}
+}
+
+// Cancel cancels the context passed to the function of the Future. It does not wait for the
+// function to return, use Await for that. Cancel has no effect once the Future completed.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+}
+
+// cancelled returns ErrCancelled wrapping the error of ctx
+func cancelled(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrCancelled, ctx.Err())
+}
+
 // Async wraps a function returning a value of type T and returns a Future[T]
+//
+// f receives a context derived from ctx, which is canceled by Future.Cancel
 func Async[T any](ctx context.Context, f func(context.Context) (T, error)) *Future[T] {
 	var result T
 	var err error
 
 	done := make(chan struct{})
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
 
 	go func() {
+		defer cancel()
 		defer func() {
 			if r := recover(); r != nil {
 				switch x := r.(type) {
@@ -47,5 +100,8 @@ func Async[T any](ctx context.Context, f func(context.Context) (T, error)) *Futu
 			<-done
 			return result, err
 		},
+		done:   done,
+		cancel: cancel,
+		ctx:    parent,
 	}
 }
@@ -325,3 +325,54 @@ func TestErrCancelled(t *testing.T) {
 		t.Fatalf("expected error message 'cancelled', got: %s", ErrCancelled.Error())
 	}
 }
+
+// TestAwaitCtx verifies AwaitCtx returns ErrCancelled when the context is done before the Future.
+func TestAwaitCtx(t *testing.T) {
+	future := Async(context.Background(), func(ctx context.Context) (int, error) {
+		time.Sleep(50 * time.Millisecond)
+		return 42, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := future.AwaitCtx(ctx)
+	if !errors.Is(err, ErrCancelled) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ErrCancelled wrapping context.DeadlineExceeded, got: %v", err)
+	}
+
+	result, err := future.AwaitCtx(context.Background())
+	if err != nil || result != 42 {
+		t.Fatalf("expected 42/nil after giving up once, got: %d/%v", result, err)
+	}
+}
+
+// TestAwaitCtxCompleted verifies AwaitCtx returns the result of a completed Future even if the context is done.
+func TestAwaitCtxCompleted(t *testing.T) {
+	future := Async(context.Background(), func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	_, _ = future.Await()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 100 {
+		result, err := future.AwaitCtx(ctx)
+		if err != nil || result != 42 {
+			t.Fatalf("expected 42/nil, got: %d/%v", result, err)
+		}
+	}
+}
+
+// TestCancel verifies Cancel cancels the context passed to the function.
+func TestCancel(t *testing.T) {
+	future := Async(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	future.Cancel()
+	_, err := future.Await()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+}
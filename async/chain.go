@@ -0,0 +1,58 @@
+package async
+
+import "context"
+
+// Then returns a Future running fn with the value of f once f completed successfully. An error of f
+// is passed on without calling fn. The returned Future uses the context f was created with.
+func Then[T, U any](f *Future[T], fn func(context.Context, T) (U, error)) *Future[U] {
+	return Async(f.ctx, func(ctx context.Context) (U, error) {
+		value, err := f.AwaitCtx(ctx)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return fn(ctx, value)
+	})
+}
+
+// Map returns a Future holding the value of f transformed by fn. An error of f is passed on without calling fn.
+func Map[T, U any](f *Future[T], fn func(T) U) *Future[U] {
+	return Then(f, func(_ context.Context, value T) (U, error) {
+		return fn(value), nil
+	})
+}
+
+// FlatMap returns a Future holding the outcome of the Future returned by fn for the value of f. An error
+// of f is passed on without calling fn.
+func FlatMap[T, U any](f *Future[T], fn func(context.Context, T) *Future[U]) *Future[U] {
+	return Then(f, func(ctx context.Context, value T) (U, error) {
+		return fn(ctx, value).AwaitCtx(ctx)
+	})
+}
+
+// Catch returns a Future recovering from an error of f by calling fn with it. A value of f is passed
+// on without calling fn. If the returned Future is canceled before f completed, it fails with
+// ErrCancelled without calling fn.
+func Catch[T any](f *Future[T], fn func(context.Context, error) (T, error)) *Future[T] {
+	return Async(f.ctx, func(ctx context.Context) (T, error) {
+		if err := f.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		value, err := f.Await()
+		if err != nil {
+			return fn(ctx, err)
+		}
+		return value, nil
+	})
+}
+
+// Finally returns a Future calling fn once f completed, successful or not, and passing on the outcome of f.
+// Canceling the returned Future does not stop it from waiting for f.
+func Finally[T any](f *Future[T], fn func()) *Future[T] {
+	return Async(f.ctx, func(context.Context) (T, error) {
+		value, err := f.Await()
+		fn()
+		return value, err
+	})
+}
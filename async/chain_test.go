@@ -0,0 +1,138 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// TestThen verifies Then and Map transform values and pass on errors.
+func TestThen(t *testing.T) {
+	ctx := context.Background()
+
+	future := Map(Then(Async(ctx, func(ctx context.Context) (int, error) {
+		return 21, nil
+	}), func(_ context.Context, v int) (int, error) {
+		return v * 2, nil
+	}), strconv.Itoa)
+
+	result, err := future.Await()
+	if err != nil || result != "42" {
+		t.Fatalf("expected '42'/nil, got: %q/%v", result, err)
+	}
+
+	expectedErr := errors.New("test error")
+	called := false
+	_, err = Then(Async(ctx, func(ctx context.Context) (int, error) {
+		return 0, expectedErr
+	}), func(_ context.Context, v int) (int, error) {
+		called = true
+		return v, nil
+	}).Await()
+	if err != expectedErr {
+		t.Fatalf("expected error %v, got: %v", expectedErr, err)
+	}
+	if called {
+		t.Fatalf("expected fn not to be called on error")
+	}
+}
+
+// TestFlatMap verifies FlatMap waits for the Future returned by fn.
+func TestFlatMap(t *testing.T) {
+	ctx := context.Background()
+
+	future := FlatMap(Async(ctx, func(ctx context.Context) (int, error) {
+		return 42, nil
+	}), func(ctx context.Context, v int) *Future[string] {
+		return Async(ctx, func(ctx context.Context) (string, error) {
+			return strconv.Itoa(v), nil
+		})
+	})
+
+	result, err := future.Await()
+	if err != nil || result != "42" {
+		t.Fatalf("expected '42'/nil, got: %q/%v", result, err)
+	}
+}
+
+// TestCatchFinally verifies Catch recovers from errors and Finally runs regardless of the outcome.
+func TestCatchFinally(t *testing.T) {
+	ctx := context.Background()
+	finally := 0
+
+	future := Finally(Catch(Async(ctx, func(ctx context.Context) (int, error) {
+		return 0, errors.New("test error")
+	}), func(_ context.Context, err error) (int, error) {
+		return 42, nil
+	}), func() {
+		finally++
+	})
+
+	result, err := future.Await()
+	if err != nil || result != 42 {
+		t.Fatalf("expected 42/nil, got: %d/%v", result, err)
+	}
+
+	expectedErr := errors.New("test error")
+	_, err = Finally(Async(ctx, func(ctx context.Context) (int, error) {
+		return 0, expectedErr
+	}), func() {
+		finally++
+	}).Await()
+	if err != expectedErr {
+		t.Fatalf("expected error %v, got: %v", expectedErr, err)
+	}
+	if finally != 2 {
+		t.Fatalf("expected finally to run twice, ran %d times", finally)
+	}
+}
+
+// TestCatchCancelled verifies Catch does not call its function when canceled before the Future completed.
+func TestCatchCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	source := Async(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		return 0, errors.New("test error")
+	})
+
+	caught := false
+	future := Catch(source, func(_ context.Context, err error) (int, error) {
+		caught = true
+		return 42, nil
+	})
+	future.Cancel()
+	_, err := future.Await()
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("expected ErrCancelled, got: %v", err)
+	}
+	if caught {
+		t.Fatal("expected Catch not to be called for its own cancellation")
+	}
+}
+
+// TestFinallyWaits verifies Finally calls its function only after the Future completed, even when canceled.
+func TestFinallyWaits(t *testing.T) {
+	release := make(chan struct{})
+	completed := false
+	source := Async(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		completed = true
+		return 42, nil
+	})
+
+	var completedBeforeFinally bool
+	future := Finally(source, func() {
+		completedBeforeFinally = completed
+	})
+	future.Cancel()
+	close(release)
+	result, err := future.Await()
+	if err != nil || result != 42 {
+		t.Fatalf("expected 42/nil, got: %d/%v", result, err)
+	}
+	if !completedBeforeFinally {
+		t.Fatal("expected Finally to run after the Future completed")
+	}
+}
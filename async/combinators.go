@@ -0,0 +1,118 @@
+package async
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoFutures is returned by Any and Race when called without futures
+var ErrNoFutures = errors.New("no futures")
+
+// Result holds the outcome of a Future
+type Result[T any] struct {
+	// Value is the value of the Future, the zero value if Err is set
+	Value T
+
+	// Err is the error of the Future
+	Err error
+}
+
+// completions returns a channel receiving the index of each future once it completed. The goroutines
+// waiting for the futures return once stop is closed.
+func completions[T any](futures []*Future[T], stop <-chan struct{}) <-chan int {
+	completed := make(chan int, len(futures))
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.done:
+				completed <- i
+			case <-stop:
+			}
+		}()
+	}
+	return completed
+}
+
+// All waits for all futures and returns their values in order. It returns the first error
+// of a future as soon as it occurs, or ErrCancelled if ctx is done first.
+func All[T any](ctx context.Context, futures []*Future[T]) ([]T, error) {
+	results := make([]T, len(futures))
+	stop := make(chan struct{})
+	defer close(stop)
+	completed := completions(futures, stop)
+	for range futures {
+		select {
+		case i := <-completed:
+			value, err := futures[i].Await()
+			if err != nil {
+				return nil, err
+			}
+			results[i] = value
+		case <-ctx.Done():
+			return nil, cancelled(ctx)
+		}
+	}
+	return results, nil
+}
+
+// AllSettled waits for all futures and returns their outcomes in order, regardless of errors.
+// It returns ErrCancelled if ctx is done first.
+func AllSettled[T any](ctx context.Context, futures []*Future[T]) ([]Result[T], error) {
+	results := make([]Result[T], len(futures))
+	stop := make(chan struct{})
+	defer close(stop)
+	completed := completions(futures, stop)
+	for range futures {
+		select {
+		case i := <-completed:
+			value, err := futures[i].Await()
+			results[i] = Result[T]{Value: value, Err: err}
+		case <-ctx.Done():
+			return nil, cancelled(ctx)
+		}
+	}
+	return results, nil
+}
+
+// Any returns the value of the first future completing without error. If all futures fail,
+// their errors are returned joined in order. It returns ErrCancelled if ctx is done first.
+func Any[T any](ctx context.Context, futures []*Future[T]) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+	errs := make([]error, len(futures))
+	stop := make(chan struct{})
+	defer close(stop)
+	completed := completions(futures, stop)
+	for range futures {
+		select {
+		case i := <-completed:
+			value, err := futures[i].Await()
+			if err == nil {
+				return value, nil
+			}
+			errs[i] = err
+		case <-ctx.Done():
+			return zero, cancelled(ctx)
+		}
+	}
+	return zero, errors.Join(errs...)
+}
+
+// Race returns the outcome of the first future completing, whether it failed or not.
+// It returns ErrCancelled if ctx is done first.
+func Race[T any](ctx context.Context, futures []*Future[T]) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	select {
+	case i := <-completions(futures, stop):
+		return futures[i].Await()
+	case <-ctx.Done():
+		return zero, cancelled(ctx)
+	}
+}
@@ -0,0 +1,131 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// delayed returns a Future completing with value and err after d.
+func delayed[T any](d time.Duration, value T, err error) *Future[T] {
+	return Async(context.Background(), func(ctx context.Context) (T, error) {
+		time.Sleep(d)
+		return value, err
+	})
+}
+
+// TestAll verifies All returns values in order or the first error.
+func TestAll(t *testing.T) {
+	ctx := context.Background()
+
+	result, err := All(ctx, []*Future[int]{delayed(30*time.Millisecond, 1, nil), delayed(10*time.Millisecond, 2, nil)})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !reflect.DeepEqual(result, []int{1, 2}) {
+		t.Fatalf("expected [1 2], got: %v", result)
+	}
+
+	expectedErr := errors.New("test error")
+	start := time.Now()
+	_, err = All(ctx, []*Future[int]{delayed(time.Second, 1, nil), delayed(10*time.Millisecond, 0, expectedErr)})
+	if err != expectedErr {
+		t.Fatalf("expected error %v, got: %v", expectedErr, err)
+	}
+	if time.Since(start) >= time.Second {
+		t.Fatalf("expected All to return on first error")
+	}
+}
+
+// TestAllSettled verifies AllSettled returns all outcomes in order.
+func TestAllSettled(t *testing.T) {
+	expectedErr := errors.New("test error")
+	result, err := AllSettled(context.Background(), []*Future[int]{delayed(0, 1, nil), delayed(0, 0, expectedErr)})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	expected := []Result[int]{{Value: 1}, {Err: expectedErr}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, got: %v", expected, result)
+	}
+}
+
+// TestAny verifies Any returns the first success or all errors joined.
+func TestAny(t *testing.T) {
+	ctx := context.Background()
+	err1, err2 := errors.New("error 1"), errors.New("error 2")
+
+	result, err := Any(ctx, []*Future[int]{delayed(0, 0, err1), delayed(20*time.Millisecond, 2, nil)})
+	if err != nil || result != 2 {
+		t.Fatalf("expected 2/nil, got: %d/%v", result, err)
+	}
+
+	_, err = Any(ctx, []*Future[int]{delayed(0, 0, err1), delayed(0, 0, err2)})
+	if !errors.Is(err, err1) || !errors.Is(err, err2) {
+		t.Fatalf("expected joined errors, got: %v", err)
+	}
+
+	if _, err = Any[int](ctx, nil); !errors.Is(err, ErrNoFutures) {
+		t.Fatalf("expected ErrNoFutures, got: %v", err)
+	}
+}
+
+// TestRace verifies Race returns the outcome of the first completion, even if it failed.
+func TestRace(t *testing.T) {
+	expectedErr := errors.New("test error")
+	_, err := Race(context.Background(), []*Future[int]{delayed(time.Second, 1, nil), delayed(0, 0, expectedErr)})
+	if err != expectedErr {
+		t.Fatalf("expected error %v, got: %v", expectedErr, err)
+	}
+}
+
+// TestCombinatorsContext verifies the combinators return when the context is done first.
+func TestCombinatorsContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	futures := []*Future[int]{delayed(time.Second, 1, nil)}
+
+	if _, err := All(ctx, futures); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("All: expected context.DeadlineExceeded, got: %v", err)
+	}
+	if _, err := AllSettled(ctx, futures); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AllSettled: expected context.DeadlineExceeded, got: %v", err)
+	}
+	if _, err := Any(ctx, futures); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Any: expected context.DeadlineExceeded, got: %v", err)
+	}
+	if _, err := Race(ctx, futures); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Race: expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+// TestCombinatorsStop verifies returning early does not leave goroutines waiting for pending futures.
+func TestCombinatorsStop(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	futures := []*Future[int]{delayed(0, 1, nil)}
+	for range 10 {
+		futures = append(futures, Async(context.Background(), func(ctx context.Context) (int, error) {
+			<-release
+			return 0, nil
+		}))
+	}
+	_, _ = futures[0].Await()
+	before := runtime.NumGoroutine()
+
+	for range 10 {
+		if v, err := Race(context.Background(), futures); err != nil || v != 1 {
+			t.Fatalf("expected 1/nil, got %d/%v", v, err)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d goroutines, got %d", before, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
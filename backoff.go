@@ -2,8 +2,11 @@ package reuse
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math/rand/v2"
+	"math"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -16,55 +19,271 @@ var delays = []time.Duration{
 	60 * time.Second, 60 * time.Second,
 }
 
+var (
+	// defaultPolicyMu guards defaultPolicy against concurrent SetDelays calls.
+	defaultPolicyMu sync.RWMutex
+
+	// defaultPolicy is the policy used by Do and DoCtx.
+	defaultPolicy = RetryPolicy{
+		Delays: slices.Clone(delays),
+		Jitter: ProportionalJitter(0.25, nil),
+	}
+)
+
 // SetDelays sets the custom delay durations for retry logic by replacing the default delay slice.
+// An empty slice makes Do, DoCtx and DoValue give up without calling the request.
+//
+// Deprecated: the default schedule is shared by every caller of Do and DoCtx in the binary.
+// Use a RetryPolicy owned by the call site instead.
 func SetDelays(d []time.Duration) {
-	delays = d
+	defaultPolicyMu.Lock()
+	defer defaultPolicyMu.Unlock()
+	defaultPolicy.Delays = slices.Clone(d)
 }
 
-// RequestCtx defines a function that processes a request with a context and returns an error.
-type RequestCtx func(ctx context.Context) error
+// DefaultRetryPolicy returns a copy of the policy used by Do and DoCtx. Changing the copy,
+// including its Delays, does not affect the default policy.
+func DefaultRetryPolicy() *RetryPolicy {
+	defaultPolicyMu.RLock()
+	defer defaultPolicyMu.RUnlock()
+	p := defaultPolicy
+	p.Delays = slices.Clone(defaultPolicy.Delays)
+	return &p
+}
 
-// Request defines a function that processes a request and returns an error.
-type Request func() error
+// RetryPolicy describes how often and how long a request is retried.
+//
+// The zero value retries immediately and without limit until the context is canceled,
+// so at least MaxAttempts or MaxElapsedTime should be set.
+type RetryPolicy struct {
 
-// DoCtx executes a request until it succeeds or the context is canceled.
-// It will retry the request with exponential backoff.
-func DoCtx(ctx context.Context, requestCtx RequestCtx) error {
-	for _, delay := range delays {
-		err := requestCtx(ctx)
+	// BaseDelay is the delay waited after the first failed attempt.
+	BaseDelay time.Duration
+
+	// Multiplier is applied to the delay after each failed attempt. Values below 1 result in a
+	// constant delay of BaseDelay.
+	Multiplier float64
+
+	// MaxDelay caps a single delay, zero means no cap. Delays hinted by RetryAfter are capped by the
+	// largest entry of Delays if MaxDelay is zero.
+	MaxDelay time.Duration
+
+	// MaxAttempts is the maximum number of calls to the request, zero means no limit.
+	MaxAttempts int
+
+	// MaxElapsedTime stops retrying once the next wait would end after this much time has passed
+	// since the first attempt, zero means no limit.
+	MaxElapsedTime time.Duration
+
+	// Jitter randomizes each delay, nil means NoJitter.
+	Jitter JitterStrategy
+
+	// Delays is an explicit schedule replacing BaseDelay and Multiplier. The last entry is repeated
+	// when there are more attempts than entries. If MaxAttempts is zero, len(Delays) attempts are made.
+	Delays []time.Duration
+
+	// Retryable classifies errors, returning false stops retrying and returns the error as is.
+	// nil retries every error not wrapped with Permanent.
+	Retryable func(err error) bool
+
+	// CollectErrors keeps the error of every attempt in RetryError.Errors.
+	CollectErrors bool
+
+	// OnRetry is called before each wait with the failed attempt (1-based), its error and the delay about to be waited.
+	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// OnGiveUp is called once with the number of attempts made and the error about to be returned
+	// when the request did not succeed.
+	OnGiveUp func(attempts int, err error)
+
+	// Budget limits retries across all policies sharing it, nil means no limit. Successful requests
+	// are deposited, each retry is withdrawn.
+	Budget *RetryBudget
+
+	// Clock is used to measure elapsed time and to wait between attempts, nil means SystemClock.
+	Clock Clock
+}
+
+// Do executes a request until it succeeds, the policy is exhausted or the context is canceled.
+// Errors wrapped with Permanent or rejected by Retryable are returned immediately, the former unwrapped.
+// Giving up returns a *RetryError wrapping the error of the final attempt.
+func (p *RetryPolicy) Do(ctx context.Context, requestCtx RequestCtx) error {
+	_, err := retry(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, requestCtx(ctx)
+	})
+	return err
+}
+
+// retry implements the retry loop shared by RetryPolicy.Do and the DoValue functions.
+func retry[T any](ctx context.Context, p *RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	clock := clockOrSystem(p.Clock)
+	start := clock.Now()
+	maxAttempts := p.maxAttempts()
+	var (
+		previous time.Duration
+		errs     MultiError
+		err      error
+	)
+	attempt := 1
+	giveUp := func(err error) (T, error) {
+		if p.OnGiveUp != nil {
+			p.OnGiveUp(attempt, err)
+		}
+		return zero, err
+	}
+	for ; ; attempt++ {
+		var result T
+		result, err = fn(ctx)
 		if err == nil {
-			return nil
+			if p.Budget != nil {
+				p.Budget.Deposit()
+			}
+			return result, nil
+		}
+		if p.CollectErrors {
+			errs = append(errs, err)
+		}
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return giveUp(permanent.Err)
+		}
+		if p.Retryable != nil && !p.Retryable(err) {
+			return giveUp(err)
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			break
 		}
 
-		delay = multiplyDuration(delay, 0.75+rand.Float64()*0.5) // ±25%
+		delay := p.nextDelay(attempt, previous)
+		var hint RetryAfterError
+		if errors.As(err, &hint) {
+			delay = max(hint.RetryAfter(), 0)
+			if limit := p.maxRetryAfter(); limit > 0 && delay > limit {
+				delay = limit
+			}
+		}
+		if p.MaxElapsedTime > 0 && clock.Since(start)+delay > p.MaxElapsedTime {
+			break
+		}
+		if p.Budget != nil && !p.Budget.TryWithdraw() {
+			return giveUp(fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err))
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(delay):
+			return giveUp(ctx.Err())
+		case <-clock.After(delay):
 		}
+		previous = delay
 	}
 
-	return fmt.Errorf("failed after %d attempts", len(delays))
+	return giveUp(&RetryError{
+		Attempts: attempt,
+		Elapsed:  clock.Since(start),
+		Last:     err,
+		Errors:   errs,
+	})
+}
+
+// maxAttempts returns the effective attempt limit, zero meaning unlimited.
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts == 0 && len(p.Delays) > 0 {
+		return len(p.Delays)
+	}
+	return p.MaxAttempts
+}
+
+// maxRetryAfter returns the cap of a hinted delay, MaxDelay or, if unset, the largest delay of the
+// schedule. Zero means no cap.
+func (p *RetryPolicy) maxRetryAfter() time.Duration {
+	if p.MaxDelay > 0 || len(p.Delays) == 0 {
+		return p.MaxDelay
+	}
+	return slices.Max(p.Delays)
+}
+
+// nextDelay returns the jittered delay to wait after the given failed attempt (1-based).
+func (p *RetryPolicy) nextDelay(attempt int, previous time.Duration) time.Duration {
+	delay := p.Backoff(attempt)
+	if p.Jitter != nil {
+		delay = p.Jitter(p.BaseDelay, delay, previous)
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return max(delay, 0)
+}
+
+// Backoff returns the delay to wait after the given failed attempt (1-based) before jitter is applied.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if len(p.Delays) > 0 {
+		return p.Delays[min(max(attempt, 1), len(p.Delays))-1]
+	}
+	delay := float64(p.BaseDelay)
+	if p.Multiplier > 1 {
+		delay *= math.Pow(p.Multiplier, float64(max(attempt, 1)-1))
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// errNoDelays is the error of the final attempt reported when the default schedule is empty.
+var errNoDelays = errors.New("no delays scheduled")
+
+// retryDefault implements the retry loop of Do, DoCtx and DoValue using DefaultRetryPolicy. An empty
+// schedule gives up without an attempt instead of retrying without limit.
+func retryDefault[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) (T, error) {
+	p := DefaultRetryPolicy()
+	if len(p.Delays) == 0 {
+		var zero T
+		return zero, &RetryError{Last: errNoDelays}
+	}
+	return retry(ctx, p, fn)
+}
+
+// RequestCtx defines a function that processes a request with a context and returns an error.
+type RequestCtx func(ctx context.Context) error
+
+// Request defines a function that processes a request and returns an error.
+type Request func() error
+
+// DoCtx executes a request until it succeeds or the context is canceled.
+// It will retry the request with exponential backoff using DefaultRetryPolicy.
+func DoCtx(ctx context.Context, requestCtx RequestCtx) error {
+	_, err := retryDefault(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, requestCtx(ctx)
+	})
+	return err
 }
 
 // Do executes a request until it succeeds or the context is canceled.
-// It will retry the request with exponential backoff.
+// It will retry the request with exponential backoff using DefaultRetryPolicy.
 func Do(ctx context.Context, request Request) error {
-	for _, delay := range delays {
-		err := request()
-		if err == nil {
-			return nil
-		}
+	_, err := retryDefault(ctx, func(context.Context) (struct{}, error) {
+		return struct{}{}, request()
+	})
+	return err
+}
 
-		delay = multiplyDuration(delay, 0.75+rand.Float64()*0.5) // ±25%
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(delay):
-		}
-	}
+// DoValue executes a request returning a value until it succeeds or the context is canceled.
+// It will retry the request with exponential backoff using DefaultRetryPolicy and returns
+// the value of the first successful attempt.
+func DoValue[T any](ctx context.Context, request func(ctx context.Context) (T, error)) (T, error) {
+	return retryDefault(ctx, request)
+}
 
-	return fmt.Errorf("failed after %d attempts", len(delays))
+// DoValueWithPolicy executes a request returning a value as described by the policy and returns
+// the value of the first successful attempt. On error the zero value of T is returned.
+func DoValueWithPolicy[T any](ctx context.Context, p *RetryPolicy, request func(ctx context.Context) (T, error)) (T, error) {
+	return retry(ctx, p, request)
 }
 
 // multiplyDuration scales a time.Duration `d` by a float64 multiplier `mul` and returns the resulting duration.
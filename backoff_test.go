@@ -0,0 +1,293 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicyBackoff verifies the exponential schedule computed by RetryPolicy.Backoff.
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		expected []time.Duration
+	}{
+		{
+			name:     "exponential capped",
+			policy:   RetryPolicy{BaseDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second},
+			expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+		{
+			name:     "constant",
+			policy:   RetryPolicy{BaseDelay: time.Second},
+			expected: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:     "explicit schedule",
+			policy:   RetryPolicy{Delays: []time.Duration{time.Second, 3 * time.Second}},
+			expected: []time.Duration{time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, expected := range test.expected {
+				if d := test.policy.Backoff(i + 1); d != expected {
+					t.Errorf("attempt %d: expected %v, got %v", i+1, expected, d)
+				}
+			}
+		})
+	}
+}
+
+// TestRetryPolicyDoMaxAttempts verifies a policy stops after MaxAttempts calls.
+func TestRetryPolicyDoMaxAttempts(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 3}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("failing")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoSuccess verifies a policy stops retrying on the first success.
+func TestRetryPolicyDoSuccess(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 5}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("failing")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoMaxElapsedTime verifies a policy does not start a wait ending after MaxElapsedTime.
+func TestRetryPolicyDoMaxElapsedTime(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Hour, MaxElapsedTime: time.Minute}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return errors.New("failing")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoCanceled verifies a policy returns the context error when canceled while waiting.
+func TestRetryPolicyDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &RetryPolicy{BaseDelay: time.Hour}
+	err := p.Do(ctx, func(context.Context) error {
+		cancel()
+		return errors.New("failing")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestSetDelays verifies SetDelays changes the default policy without affecting copies handed out before.
+func TestSetDelays(t *testing.T) {
+	before := DefaultRetryPolicy()
+	defer SetDelays(before.Delays)
+
+	SetDelays([]time.Duration{time.Millisecond})
+	if after := DefaultRetryPolicy(); len(after.Delays) != 1 {
+		t.Fatalf("expected 1 delay, got %d", len(after.Delays))
+	}
+	if len(before.Delays) != len(delays) {
+		t.Fatalf("expected copy to keep %d delays, got %d", len(delays), len(before.Delays))
+	}
+}
+
+// TestSetDelaysEmpty verifies an empty default schedule gives up without calling the request.
+func TestSetDelaysEmpty(t *testing.T) {
+	before := DefaultRetryPolicy()
+	defer SetDelays(before.Delays)
+
+	SetDelays(nil)
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		return errors.New("failing")
+	})
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("expected ErrRetriesExhausted, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected no calls, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoPermanent verifies a permanent error stops retrying and is returned unwrapped.
+func TestRetryPolicyDoPermanent(t *testing.T) {
+	validation := errors.New("bad request")
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 5}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return fmt.Errorf("request: %w", Permanent(validation))
+	})
+	if err != validation {
+		t.Fatalf("expected %v, got %v", validation, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoRetryable verifies the Retryable classifier stops retrying for rejected errors.
+func TestRetryPolicyDoRetryable(t *testing.T) {
+	notFound := errors.New("not found")
+	p := &RetryPolicy{
+		BaseDelay:   time.Millisecond,
+		MaxAttempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, notFound)
+		},
+	}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("temporary")
+		}
+		return notFound
+	})
+	if err != notFound {
+		t.Fatalf("expected %v, got %v", notFound, err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+// TestRetryPolicyDoRetryError verifies the error returned when giving up wraps the last error and collects all errors.
+func TestRetryPolicyDoRetryError(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 3, CollectErrors: true}
+	calls := 0
+	var last error
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		last = fmt.Errorf("attempt %d", calls)
+		return last
+	})
+	if !errors.Is(err, last) {
+		t.Fatalf("expected %v to wrap %v", err, last)
+	}
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("expected %v to match ErrRetriesExhausted", err)
+	}
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected *RetryError, got %T", err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", retryErr.Attempts)
+	}
+	if len(retryErr.Errors) != 3 {
+		t.Errorf("expected 3 collected errors, got %d", len(retryErr.Errors))
+	}
+	if retryErr.Elapsed < 2*time.Millisecond {
+		t.Errorf("expected elapsed of at least 2ms, got %v", retryErr.Elapsed)
+	}
+}
+
+// TestDoValueWithPolicy verifies the value of the first successful attempt is returned.
+func TestDoValueWithPolicy(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 5}
+	calls := 0
+	v, err := DoValueWithPolicy(context.Background(), p, func(context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "partial", errors.New("failing")
+		}
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if v != "done" {
+		t.Fatalf("expected 'done', got %q", v)
+	}
+
+	v, err = DoValueWithPolicy(context.Background(), p, func(context.Context) (string, error) {
+		return "partial", Permanent(errors.New("bad request"))
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if v != "" {
+		t.Fatalf("expected zero value on error, got %q", v)
+	}
+}
+
+// TestRetryPolicyHooks verifies OnRetry is called before each wait and OnGiveUp once at the end.
+func TestRetryPolicyHooks(t *testing.T) {
+	var retries []int
+	var delaysSeen []time.Duration
+	giveUps := 0
+	p := &RetryPolicy{
+		BaseDelay:   time.Millisecond,
+		Multiplier:  2,
+		MaxAttempts: 3,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			retries = append(retries, attempt)
+			delaysSeen = append(delaysSeen, delay)
+		},
+		OnGiveUp: func(attempts int, err error) {
+			giveUps++
+			if attempts != 3 {
+				t.Errorf("expected 3 attempts on give up, got %d", attempts)
+			}
+			if !errors.Is(err, ErrRetriesExhausted) {
+				t.Errorf("expected ErrRetriesExhausted on give up, got %v", err)
+			}
+		},
+	}
+	_ = p.Do(context.Background(), func(context.Context) error {
+		return errors.New("failing")
+	})
+	if !reflect.DeepEqual(retries, []int{1, 2}) {
+		t.Errorf("expected retries [1 2], got %v", retries)
+	}
+	if !reflect.DeepEqual(delaysSeen, []time.Duration{time.Millisecond, 2 * time.Millisecond}) {
+		t.Errorf("expected delays [1ms 2ms], got %v", delaysSeen)
+	}
+	if giveUps != 1 {
+		t.Errorf("expected 1 give up, got %d", giveUps)
+	}
+}
+
+// TestDefaultRetryPolicyCopy verifies changing the Delays of a copy does not change the default schedule.
+func TestDefaultRetryPolicyCopy(t *testing.T) {
+	p := DefaultRetryPolicy()
+	p.Delays[0] = 0
+	if d := DefaultRetryPolicy().Delays[0]; d != time.Second {
+		t.Fatalf("expected default schedule to start with 1s, got %v", d)
+	}
+	if delays[0] != time.Second {
+		t.Fatalf("expected package schedule to start with 1s, got %v", delays[0])
+	}
+}
@@ -0,0 +1,158 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// ErrBulkheadFull is returned when a call can not acquire a slot because the wait queue is full
+// or the maximum wait time elapsed.
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// Bulkhead limits the number of concurrent calls to a dependency. Calls exceeding the limit wait in a
+// bounded queue for at most a maximum wait time.
+//
+// A Bulkhead composes with circuitbreaker.Execute and reuse.RetryPolicy, e.g. a retry around a bulkhead
+// around a circuit breaker:
+//
+//	err := policy.Do(ctx, func(ctx context.Context) error {
+//		return b.Do(ctx, func(ctx context.Context) error {
+//			_, err := circuitbreaker.Execute(ctx, cb, func(ctx context.Context) (struct{}, error) {
+//				return struct{}{}, request(ctx)
+//			})
+//			return err
+//		})
+//	})
+//
+// Keeping the bulkhead outside the circuit breaker prevents ErrBulkheadFull from being counted as a
+// failure of the dependency. Otherwise exclude it with circuitbreaker.WithIsFailure.
+//
+// A Bulkhead is safe for concurrent use.
+type Bulkhead struct {
+
+	// slots holds one element per call in flight.
+	slots chan struct{}
+
+	// mu protects waiting and clock.
+	mu sync.Mutex
+
+	// waiting is the number of calls waiting for a slot.
+	waiting int
+
+	// maxQueue is the maximum number of calls waiting for a slot.
+	maxQueue int
+
+	// maxWait is the maximum time a call waits for a slot, zero means waiting until the context is done.
+	maxWait time.Duration
+
+	// clock is used to time out waiting calls.
+	clock reuse.Clock
+}
+
+// New returns a Bulkhead allowing maxConcurrent calls in flight and maxQueue calls waiting for at most maxWait.
+// A maxWait of zero lets queued calls wait until their context is done.
+func New(maxConcurrent, maxQueue int, maxWait time.Duration) *Bulkhead {
+	return &Bulkhead{
+		slots:    make(chan struct{}, max(maxConcurrent, 1)),
+		maxQueue: maxQueue,
+		maxWait:  maxWait,
+		clock:    reuse.SystemClock{},
+	}
+}
+
+// SetClock replaces the clock used to time out waiting calls, nil restores reuse.SystemClock.
+func (b *Bulkhead) SetClock(clock reuse.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if clock == nil {
+		clock = reuse.SystemClock{}
+	}
+	b.clock = clock
+}
+
+// Acquire reserves a slot, waiting if none is free. It returns ErrBulkheadFull if the queue is full or
+// the maximum wait time elapsed and the context error if the context is done first. Each successful
+// Acquire must be followed by Release.
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	b.mu.Lock()
+	if b.waiting >= b.maxQueue {
+		b.mu.Unlock()
+		return ErrBulkheadFull
+	}
+	b.waiting++
+	var timeout <-chan time.Time
+	if b.maxWait > 0 {
+		timeout = b.clock.After(b.maxWait)
+	}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.waiting--
+		b.mu.Unlock()
+	}()
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return ErrBulkheadFull
+	}
+}
+
+// Release frees a slot reserved by Acquire. It panics if no slot is reserved.
+func (b *Bulkhead) Release() {
+	select {
+	case <-b.slots:
+	default:
+		panic("bulkhead: released more than acquired")
+	}
+}
+
+// InFlight returns the number of calls currently holding a slot.
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Waiting returns the number of calls currently waiting for a slot.
+func (b *Bulkhead) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.waiting
+}
+
+// Do runs requestCtx once a slot is available.
+func (b *Bulkhead) Do(ctx context.Context, requestCtx reuse.RequestCtx) error {
+	_, err := Execute(ctx, b, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, requestCtx(ctx)
+	})
+	return err
+}
+
+// Wrap returns a reuse.RequestCtx running requestCtx within the Bulkhead, e.g. to pass it to reuse.DoCtx.
+func (b *Bulkhead) Wrap(requestCtx reuse.RequestCtx) reuse.RequestCtx {
+	return func(ctx context.Context) error {
+		return b.Do(ctx, requestCtx)
+	}
+}
+
+// Execute runs fn once a slot of the Bulkhead is available and returns its result.
+func Execute[T any](ctx context.Context, b *Bulkhead, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if err := b.Acquire(ctx); err != nil {
+		return zero, err
+	}
+	defer b.Release()
+	return fn(ctx)
+}
@@ -0,0 +1,105 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+	"github.com/sascha-andres/reuse/circuitbreaker"
+)
+
+// TestBulkhead verifies the concurrency limit, the bounded queue and the maximum wait time.
+func TestBulkhead(t *testing.T) {
+	b := New(1, 1, time.Minute)
+	fc := reuse.NewFakeClock(time.Now())
+	b.SetClock(fc)
+
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected free slot, got %v", err)
+	}
+
+	queued := make(chan error)
+	go func() {
+		queued <- b.Acquire(context.Background())
+	}()
+	fc.BlockUntil(1)
+	if b.Waiting() != 1 {
+		t.Fatalf("expected 1 waiting call, got %d", b.Waiting())
+	}
+
+	if err := b.Acquire(context.Background()); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull with full queue, got %v", err)
+	}
+
+	fc.Advance(time.Minute)
+	if err := <-queued; !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull after max wait, got %v", err)
+	}
+
+	go func() {
+		queued <- b.Acquire(context.Background())
+	}()
+	fc.BlockUntil(1)
+	b.Release()
+	if err := <-queued; err != nil {
+		t.Fatalf("expected queued call to acquire released slot, got %v", err)
+	}
+	if b.InFlight() != 1 {
+		t.Fatalf("expected 1 call in flight, got %d", b.InFlight())
+	}
+}
+
+// TestBulkheadCanceled verifies a waiting call returns the context error.
+func TestBulkheadCanceled(t *testing.T) {
+	b := New(1, 1, 0)
+	_ = b.Acquire(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestBulkheadComposition verifies a bulkhead composes with a circuit breaker and a retry policy.
+func TestBulkheadComposition(t *testing.T) {
+	b := New(2, 0, 0)
+	cb := circuitbreaker.New(circuitbreaker.WithMaxFailures(5))
+	policy := &reuse.RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 3}
+
+	calls := 0
+	err := policy.Do(context.Background(), func(ctx context.Context) error {
+		return b.Do(ctx, func(ctx context.Context) error {
+			_, err := circuitbreaker.Execute(ctx, cb, func(context.Context) (struct{}, error) {
+				calls++
+				if calls < 3 {
+					return struct{}{}, errors.New("failing")
+				}
+				return struct{}{}, nil
+			})
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if s := cb.Snapshot(); s.Failures != 2 || s.Successes != 1 {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
+	if b.InFlight() != 0 {
+		t.Fatalf("expected all slots to be released, got %d in flight", b.InFlight())
+	}
+}
+
+// TestBulkheadReleaseWithoutAcquire verifies Release panics instead of blocking when no slot is reserved.
+func TestBulkheadReleaseWithoutAcquire(t *testing.T) {
+	b := New(1, 0, 0)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Release to panic")
+		}
+	}()
+	b.Release()
+}
@@ -1,9 +1,13 @@
 package circuitbreaker
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/sascha-andres/reuse"
 )
 
 // CircuitBreaker states
@@ -17,6 +21,16 @@ const (
 
 	// StateHalfOpen represents the half-open state of a CircuitBreaker, where limited requests are allowed to test recovery.
 	StateHalfOpen = "Half-Open"
+
+	// StateForcedOpen represents a manually opened CircuitBreaker, where requests are blocked until Reset is called.
+	StateForcedOpen = "Forced-Open"
+
+	// StateForcedClosed represents a manually closed CircuitBreaker, where requests are allowed and recorded,
+	// but never open the circuit until Reset is called.
+	StateForcedClosed = "Forced-Closed"
+
+	// StateDisabled represents a disabled CircuitBreaker, where requests are allowed and not recorded until Reset is called.
+	StateDisabled = "Disabled"
 )
 
 // CircuitBreaker struct
@@ -42,64 +56,405 @@ type CircuitBreaker struct {
 
 	// fn is the function executed by the CircuitBreaker, returning an error if the task fails.
 	fn WorkFunc
+
+	// clock provides the current time for tracking failures and timeouts.
+	clock reuse.Clock
+
+	// maxHalfOpenProbes is the maximum number of concurrent calls allowed in the half-open state, zero means no limit.
+	maxHalfOpenProbes int
+
+	// requiredSuccesses is the number of consecutive successful probes needed to transition from half-open to closed.
+	requiredSuccesses int
+
+	// halfOpenProbes is the number of probes currently in flight in the half-open state.
+	halfOpenProbes int
+
+	// halfOpenSuccesses is the number of successful probes since entering the half-open state.
+	halfOpenSuccesses int
+
+	// generation is incremented on each state transition, so probes admitted by an earlier half-open
+	// period can be told apart from current ones.
+	generation uint64
+
+	// window records recent calls if the CircuitBreaker trips on failure rates instead of consecutive failures.
+	window *window
+
+	// onStateChange is called after each state transition, nil if not set.
+	onStateChange func(name, from, to string)
+
+	// logger receives failures and state transitions, nil if not set.
+	logger *slog.Logger
+
+	// pending holds the state transitions not yet passed to onStateChange and logger.
+	pending []stateChange
+
+	// lastTransition is the time of the last state transition, or the creation time.
+	lastTransition time.Time
+
+	// calls is the number of calls executed.
+	calls uint64
+
+	// successes is the number of calls executed successfully.
+	successes uint64
+
+	// totalFailures is the number of calls that failed.
+	totalFailures uint64
+
+	// rejected is the number of calls short-circuited.
+	rejected uint64
+
+	// name identifies the CircuitBreaker in logs and snapshots.
+	name string
+
+	// isFailure decides whether an error returned by a call counts as failure, nil counts all errors.
+	isFailure func(err error) bool
+
+	// openBackoff computes the open duration from the number of consecutive openings, nil uses timeout.
+	openBackoff *reuse.RetryPolicy
+
+	// fallbackSuccesses is the number of fallbacks returning without error.
+	fallbackSuccesses uint64
+
+	// fallbackFailures is the number of fallbacks returning an error.
+	fallbackFailures uint64
+
+	// reopenings is the number of consecutive failed half-open probes since the circuit was last closed.
+	reopenings int
+}
+
+// stateChange is a transition between two states.
+type stateChange struct {
+
+	// from is the state left.
+	from string
+
+	// to is the state entered.
+	to string
 }
 
 // NewCircuitBreaker initializes and returns a new CircuitBreaker instance with specified maxFailures, timeout, and function.
+// fn is only used by Call and may be nil if the CircuitBreaker is used with Execute.
 func NewCircuitBreaker(maxFailures int, timeout time.Duration, fn WorkFunc) *CircuitBreaker {
-	return &CircuitBreaker{
+	cb := &CircuitBreaker{
 		state:       StateClosed,
 		maxFailures: maxFailures,
 		timeout:     timeout,
 		fn:          fn,
+		clock:       reuse.SystemClock{},
+
+		requiredSuccesses: 1,
+	}
+	cb.lastTransition = cb.clock.Now()
+	return cb
+}
+
+// NewSlidingWindowCircuitBreaker initializes and returns a new CircuitBreaker tripping on the failure rate or
+// slow call rate of the calls within sw instead of consecutive failures.
+// fn is only used by Call and may be nil if the CircuitBreaker is used with Execute.
+func NewSlidingWindowCircuitBreaker(sw SlidingWindow, timeout time.Duration, fn WorkFunc) *CircuitBreaker {
+	cb := NewCircuitBreaker(0, timeout, fn)
+	cb.window = newWindow(sw)
+	return cb
+}
+
+// SetClock replaces the clock used to track failures and timeouts, nil restores reuse.SystemClock.
+// Before the first state transition, the time of the last transition is reset to the time of clock.
+func (cb *CircuitBreaker) SetClock(clock reuse.Clock) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if clock == nil {
+		clock = reuse.SystemClock{}
+	}
+	cb.clock = clock
+	if cb.generation == 0 {
+		cb.lastTransition = clock.Now()
+	}
+}
+
+// SetHalfOpenLimits limits the half-open state to maxProbes concurrent calls, rejecting further calls
+// with ErrOpenState, and requires requiredSuccesses consecutive successful probes before closing.
+// A maxProbes of zero allows all calls, requiredSuccesses below one is treated as one.
+func (cb *CircuitBreaker) SetHalfOpenLimits(maxProbes, requiredSuccesses int) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.maxHalfOpenProbes = maxProbes
+	cb.requiredSuccesses = max(requiredSuccesses, 1)
+}
+
+// OnStateChange registers fn to be called after each state transition with the name of the CircuitBreaker,
+// so one fn can be shared by several breakers. fn is called synchronously after the CircuitBreaker
+// released its lock, so it may call other methods of the CircuitBreaker.
+func (cb *CircuitBreaker) OnStateChange(fn func(name, from, to string)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onStateChange = fn
+}
+
+// SetLogger sets a logger receiving failed calls as debug and state transitions as info messages, nil disables logging.
+func (cb *CircuitBreaker) SetLogger(logger *slog.Logger) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.logger = logger
+}
+
+// Reset closes the circuit and clears the failures recorded. It also ends a forced or disabled state.
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.failures = 0
+	cb.reopenings = 0
+	cb.lastFailureTime = time.Time{}
+	cb.setState(StateClosed, cb.clock.Now())
+	if cb.window != nil {
+		cb.window.reset()
 	}
 }
 
+// ForceOpen opens the circuit until Reset is called, rejecting all calls with ErrOpenState,
+// e.g. to drain traffic from a dependency.
+func (cb *CircuitBreaker) ForceOpen() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setState(StateForcedOpen, cb.clock.Now())
+}
+
+// ForceClosed closes the circuit until Reset is called. Calls are still recorded, but failures never open the circuit.
+func (cb *CircuitBreaker) ForceClosed() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setState(StateForcedClosed, cb.clock.Now())
+}
+
+// Disable lets all calls pass without recording them until Reset is called.
+func (cb *CircuitBreaker) Disable() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setState(StateDisabled, cb.clock.Now())
+}
+
+// ErrOpenState is returned by Execute when the CircuitBreaker short-circuits a call.
+var ErrOpenState = errors.New("circuit breaker is open")
+
+// errPanicked is recorded as the outcome of a call whose function panicked.
+var errPanicked = errors.New("circuit breaker: call panicked")
+
+// Execute runs fn within the CircuitBreaker and returns its result. If the CircuitBreaker is open,
+// or the half-open state has reached its probe limit, fn is not called and ErrOpenState is returned.
+// A panic of fn is recorded as a failure and passed on.
+func Execute[T any](ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	probe, generation, err := cb.before()
+	if err != nil {
+		return zero, err
+	}
+
+	start := cb.now()
+	completed := false
+	defer func() {
+		if !completed {
+			cb.after(probe, generation, errPanicked, start)
+		}
+	}()
+	result, err := fn(ctx)
+	completed = true
+	cb.after(probe, generation, err, start)
+	return result, err
+}
+
+// ExecuteWithFallback runs fn like Execute. If the call is short-circuited or fails, fallback is called
+// with that error and its result is returned instead, e.g. to serve cached or default values. Errors
+// not counting as failure, see WithIsFailure, are returned without calling fallback.
+// The outcome of fallback is counted separately in the Snapshot.
+func ExecuteWithFallback[T any](ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (T, error), fallback func(ctx context.Context, err error) (T, error)) (T, error) {
+	result, err := Execute(ctx, cb, fn)
+	if err == nil || !errors.Is(err, ErrOpenState) && !cb.failure(err) {
+		return result, err
+	}
+	result, err = fallback(ctx, err)
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err != nil {
+		cb.fallbackFailures++
+	} else {
+		cb.fallbackSuccesses++
+	}
+	return result, err
+}
+
+// failure reports whether err counts as failure.
+func (cb *CircuitBreaker) failure(err error) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.isFailure == nil || cb.isFailure(err)
+}
+
 // Call executes a task within the CircuitBreaker, transitioning states based on task success or failure.
 func (cb *CircuitBreaker) Call(wg *sync.WaitGroup, taskDone chan<- Task, id int) {
 	defer wg.Done()
+	_, err := Execute(context.Background(), cb, func(context.Context) (struct{}, error) {
+		return struct{}{}, cb.fn(id)
+	})
+	taskDone <- Task{Id: id, Status: err == nil}
+}
+
+// before checks whether a call may pass, moving from open to half-open once the timeout passed.
+// It reports whether the call is a half-open probe and the generation of the state admitting it.
+func (cb *CircuitBreaker) before() (bool, uint64, error) {
 	cb.mu.Lock()
+	defer cb.unlock()
 
 	//  circuit breaker state
 	switch cb.state {
+	case StateForcedOpen:
+		cb.rejected++
+		return false, cb.generation, ErrOpenState
 	case StateOpen:
-		if time.Since(cb.lastFailureTime) > cb.timeout {
-			cb.state = StateHalfOpen
-		} else {
-			cb.mu.Unlock()
-			taskDone <- Task{Id: id}
-			return
+		if cb.clock.Since(cb.lastFailureTime) <= cb.openTimeout() {
+			cb.rejected++
+			return false, cb.generation, ErrOpenState
 		}
+		cb.setState(StateHalfOpen, cb.clock.Now())
+		fallthrough
 	case StateHalfOpen:
-		// Allow some tasks to test if the service has recovered
-		// continue with the instructions
+		// Allow a limited number of tasks to test if the service has recovered
+		if cb.maxHalfOpenProbes > 0 && cb.halfOpenProbes >= cb.maxHalfOpenProbes {
+			cb.rejected++
+			return false, cb.generation, ErrOpenState
+		}
+		cb.halfOpenProbes++
+		return true, cb.generation, nil
 	}
+	return false, cb.generation, nil
+}
 
-	cb.mu.Unlock()
-
-	// Perform the task
-	err := cb.fn(id)
-
+// now returns the current time of the clock of the CircuitBreaker.
+func (cb *CircuitBreaker) now() time.Time {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
+	return cb.clock.Now()
+}
 
-	if err != nil {
-		cb.failures++
-		fmt.Println("Task failed. Failure count:", cb.failures)
+// after records the outcome of a call started at start, transitioning states accordingly.
+// Errors rejected by isFailure are ignored apart from counting the call. Probes admitted by an earlier half-open period
+// than the current generation are only added to the totals, they neither change the state nor
+// the consecutive failures.
+func (cb *CircuitBreaker) after(probe bool, generation uint64, err error, start time.Time) {
+	cb.mu.Lock()
+	defer cb.unlock()
 
-		// If max failures reached, open the circuit
-		if cb.failures >= cb.maxFailures {
-			cb.state = StateOpen
-			cb.lastFailureTime = time.Now()
+	stale := probe && generation != cb.generation
+	if probe && !stale {
+		cb.halfOpenProbes--
+	}
+	if cb.state == StateDisabled {
+		return
+	}
+
+	failed := err == errPanicked || err != nil && (cb.isFailure == nil || cb.isFailure(err))
+	if err != nil && !failed {
+		// Errors rejected by isFailure count neither as success nor as failure
+		cb.calls++
+		return
+	}
+	now := cb.clock.Now()
+	if cb.window != nil && !probe && cb.state == StateClosed {
+		cb.window.record(now, failed, now.Sub(start))
+	}
+
+	cb.calls++
+	if failed {
+		if !stale {
+			cb.failures++
+		}
+		cb.totalFailures++
+		if cb.logger != nil {
+			cb.logger.Debug("task failed", "name", cb.name, "err", err, "failures", cb.failures)
 		}
-		taskDone <- Task{Id: id}
 	} else {
-		// Success: reset failure count
-		cb.failures = 0
-		if cb.state == StateHalfOpen {
-			cb.state = StateClosed
+		cb.successes++
+	}
+	if stale {
+		return
+	}
+
+	// If the failure threshold is reached or a probe failed, open the circuit
+	if probe && failed && cb.state == StateHalfOpen {
+		cb.reopenings++
+		cb.lastFailureTime = now
+		cb.setState(StateOpen, now)
+		return
+	}
+	if cb.state == StateClosed && cb.tripped(now, failed) {
+		cb.reopenings = 0
+		cb.lastFailureTime = now
+		cb.setState(StateOpen, now)
+		return
+	}
+	if failed {
+		return
+	}
+
+	if probe && cb.state == StateHalfOpen {
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses < cb.requiredSuccesses {
+			return
 		}
-		taskDone <- Task{Id: id, Status: true}
+		cb.setState(StateClosed, now)
+		cb.reopenings = 0
+	}
+
+	// Success: reset failure count
+	cb.failures = 0
+}
+
+// setState transitions to the state to, resetting the data kept per state, cb.mu must be held.
+// The transition is reported to onStateChange and logger by unlock.
+func (cb *CircuitBreaker) setState(to string, now time.Time) {
+	if cb.state == to {
+		return
+	}
+	cb.pending = append(cb.pending, stateChange{from: cb.state, to: to})
+	cb.state = to
+	cb.generation++
+	cb.lastTransition = now
+	cb.halfOpenProbes = 0
+	cb.halfOpenSuccesses = 0
+	if cb.window != nil {
+		cb.window.reset()
+	}
+}
+
+// unlock releases cb.mu and reports pending state transitions to onStateChange and logger.
+func (cb *CircuitBreaker) unlock() {
+	pending, onStateChange, logger, name := cb.pending, cb.onStateChange, cb.logger, cb.name
+	cb.pending = nil
+	cb.mu.Unlock()
+
+	for _, change := range pending {
+		if logger != nil {
+			logger.Info("circuit breaker state changed", "name", name, "from", change.from, "to", change.to)
+		}
+		if onStateChange != nil {
+			onStateChange(name, change.from, change.to)
+		}
+	}
+}
+
+// openTimeout returns the duration the circuit stays open, growing with each failed half-open probe
+// if an open backoff is configured, cb.mu must be held.
+func (cb *CircuitBreaker) openTimeout() time.Duration {
+	if cb.openBackoff == nil {
+		return cb.timeout
+	}
+	return cb.openBackoff.Backoff(cb.reopenings + 1)
+}
+
+// tripped reports whether the closed circuit has to open after a call, cb.mu must be held.
+func (cb *CircuitBreaker) tripped(now time.Time, failed bool) bool {
+	if cb.window != nil {
+		return cb.window.exceeded(now)
 	}
+	return failed && cb.failures >= cb.maxFailures
 }
 
 // WorkFunc defines a function type that takes an integer ID and returns an error.
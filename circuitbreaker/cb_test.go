@@ -0,0 +1,350 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// call runs a single task through the CircuitBreaker and returns its completion status.
+func call(cb *CircuitBreaker, id int) bool {
+	var wg sync.WaitGroup
+	taskDone := make(chan Task, 1)
+	wg.Add(1)
+	cb.Call(&wg, taskDone, id)
+	wg.Wait()
+	return (<-taskDone).Status
+}
+
+// TestCircuitBreakerClock verifies the open state ends once the clock passes the timeout.
+func TestCircuitBreakerClock(t *testing.T) {
+	fail := true
+	cb := NewCircuitBreaker(2, time.Minute, func(int) error {
+		if fail {
+			return errors.New("failing")
+		}
+		return nil
+	})
+	fc := reuse.NewFakeClock(time.Now())
+	cb.SetClock(fc)
+
+	call(cb, 1)
+	call(cb, 2)
+	if cb.state != StateOpen {
+		t.Fatalf("expected state %s, got %s", StateOpen, cb.state)
+	}
+
+	fail = false
+	if call(cb, 3) {
+		t.Fatal("expected call to be short-circuited while open")
+	}
+
+	fc.Advance(time.Minute + time.Second)
+	if !call(cb, 4) {
+		t.Fatal("expected call to succeed after timeout")
+	}
+	if cb.state != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, cb.state)
+	}
+}
+
+// TestExecute verifies Execute returns results, counts failures and short-circuits with ErrOpenState.
+func TestExecute(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, nil)
+	failing := errors.New("failing")
+
+	v, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil || v != 42 {
+		t.Fatalf("expected 42/nil, got %d/%v", v, err)
+	}
+
+	_, err = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, failing
+	})
+	if err != failing {
+		t.Fatalf("expected %v, got %v", failing, err)
+	}
+
+	called := false
+	_, err = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		called = true
+		return 42, nil
+	})
+	if !errors.Is(err, ErrOpenState) {
+		t.Fatalf("expected ErrOpenState, got %v", err)
+	}
+	if called {
+		t.Fatal("expected fn not to be called while open")
+	}
+}
+
+// TestHalfOpenLimits verifies concurrent probes are limited and closing requires consecutive successes.
+func TestHalfOpenLimits(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, nil)
+	cb.SetHalfOpenLimits(1, 2)
+	fc := reuse.NewFakeClock(time.Now())
+	cb.SetClock(fc)
+
+	_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	})
+	fc.Advance(2 * time.Minute)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		done <- err
+	}()
+	<-started
+
+	if _, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 2, nil
+	}); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("expected excess probe to be rejected, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+	if cb.state != StateHalfOpen {
+		t.Fatalf("expected state %s after one success, got %s", StateHalfOpen, cb.state)
+	}
+
+	if _, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 3, nil
+	}); err != nil {
+		t.Fatalf("expected second probe to succeed, got %v", err)
+	}
+	if cb.state != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, cb.state)
+	}
+}
+
+// TestManualOverrides verifies forced and disabled states ignore failures and timeouts until Reset.
+func TestManualOverrides(t *testing.T) {
+	cb := New(WithMaxFailures(1), WithTimeout(time.Minute))
+	fc := reuse.NewFakeClock(time.Now())
+	cb.SetClock(fc)
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+
+	cb.ForceOpen()
+	fc.Advance(time.Hour)
+	if _, err := Execute(context.Background(), cb, failing); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("expected ErrOpenState while forced open, got %v", err)
+	}
+
+	cb.ForceClosed()
+	_, _ = Execute(context.Background(), cb, failing)
+	if s := cb.Snapshot(); s.State != StateForcedClosed || s.Failures != 1 {
+		t.Fatalf("expected recorded failure while forced closed, got %+v", s)
+	}
+
+	cb.Disable()
+	_, _ = Execute(context.Background(), cb, failing)
+	if s := cb.Snapshot(); s.State != StateDisabled || s.Failures != 1 {
+		t.Fatalf("expected unrecorded failure while disabled, got %+v", s)
+	}
+
+	cb.Reset()
+	if s := cb.Snapshot(); s.State != StateClosed || s.ConsecutiveFailures != 0 {
+		t.Fatalf("expected reset breaker, got %+v", s)
+	}
+	_, _ = Execute(context.Background(), cb, failing)
+	if s := cb.Snapshot(); s.State != StateOpen {
+		t.Fatalf("expected state %s after reset, got %s", StateOpen, s.State)
+	}
+}
+
+// TestExecuteWithFallback verifies the fallback replaces failed and rejected calls and is counted separately.
+func TestExecuteWithFallback(t *testing.T) {
+	cb := New(WithMaxFailures(1), WithTimeout(time.Minute))
+	var reasons []error
+	fallback := func(_ context.Context, err error) (string, error) {
+		reasons = append(reasons, err)
+		if len(reasons) > 1 {
+			return "", errors.New("no cache")
+		}
+		return "cached", nil
+	}
+	failing := errors.New("failing")
+
+	v, err := ExecuteWithFallback(context.Background(), cb, func(context.Context) (string, error) {
+		return "", failing
+	}, fallback)
+	if err != nil || v != "cached" {
+		t.Fatalf("expected cached/nil, got %q/%v", v, err)
+	}
+	_, err = ExecuteWithFallback(context.Background(), cb, func(context.Context) (string, error) {
+		return "fresh", nil
+	}, fallback)
+	if err == nil {
+		t.Fatal("expected fallback error")
+	}
+	if len(reasons) != 2 || reasons[0] != failing || !errors.Is(reasons[1], ErrOpenState) {
+		t.Fatalf("unexpected fallback reasons %v", reasons)
+	}
+	if s := cb.Snapshot(); s.FallbackSuccesses != 1 || s.FallbackFailures != 1 || s.Failures != 1 || s.Rejected != 1 {
+		t.Fatalf("unexpected snapshot %+v", s)
+	}
+}
+
+// TestExecuteWithFallbackNotFailure verifies errors not counting as failure are returned without calling the fallback.
+func TestExecuteWithFallbackNotFailure(t *testing.T) {
+	notFound := errors.New("not found")
+	cb := New(WithIsFailure(func(err error) bool {
+		return !errors.Is(err, notFound)
+	}))
+	called := false
+	_, err := ExecuteWithFallback(context.Background(), cb, func(context.Context) (string, error) {
+		return "", notFound
+	}, func(context.Context, error) (string, error) {
+		called = true
+		return "cached", nil
+	})
+	if err != notFound {
+		t.Fatalf("expected %v, got %v", notFound, err)
+	}
+	if called {
+		t.Fatal("expected fallback not to be called")
+	}
+}
+
+// TestStaleProbe verifies a probe admitted by an earlier half-open period neither closes the circuit
+// nor frees a probe slot of the current half-open period.
+func TestStaleProbe(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	cb := New(WithMaxFailures(1), WithTimeout(time.Minute), WithClock(fc), WithHalfOpenLimits(1, 1))
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+	// probe starts a call blocking until release is closed and returns a channel receiving its error.
+	probe := func(release <-chan struct{}, err error) <-chan error {
+		started := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			_, e := Execute(context.Background(), cb, func(context.Context) (int, error) {
+				close(started)
+				<-release
+				return 0, err
+			})
+			done <- e
+		}()
+		<-started
+		return done
+	}
+
+	_, _ = Execute(context.Background(), cb, failing)
+	fc.Advance(2 * time.Minute)
+	releaseA := make(chan struct{})
+	doneA := probe(releaseA, nil)
+
+	cb.Reset()
+	_, _ = Execute(context.Background(), cb, failing)
+	fc.Advance(2 * time.Minute)
+	releaseB := make(chan struct{})
+	doneB := probe(releaseB, errors.New("failing"))
+
+	close(releaseA)
+	<-doneA
+	if s := cb.Snapshot(); s.State != StateHalfOpen {
+		t.Fatalf("expected stale probe to leave state %s, got %s", StateHalfOpen, s.State)
+	}
+	if _, err := Execute(context.Background(), cb, failing); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("expected probe limit to still be reached, got %v", err)
+	}
+
+	close(releaseB)
+	<-doneB
+	if s := cb.Snapshot(); s.State != StateOpen {
+		t.Fatalf("expected failed probe to open the circuit, got %s", s.State)
+	}
+	if cb.halfOpenProbes != 0 {
+		t.Fatalf("expected no probes in flight, got %d", cb.halfOpenProbes)
+	}
+}
+
+// TestExecutePanic verifies a panicking probe is recorded as a failure and releases its probe slot.
+func TestExecutePanic(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	cb := New(WithMaxFailures(1), WithTimeout(time.Minute), WithClock(fc), WithHalfOpenLimits(1, 1))
+	_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	})
+	fc.Advance(2 * time.Minute)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the panic to be passed on")
+			}
+		}()
+		_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+			panic("probe")
+		})
+	}()
+	if s := cb.Snapshot(); s.State != StateOpen || s.Failures != 2 {
+		t.Fatalf("expected open state after 2 failures, got %+v", s)
+	}
+
+	fc.Advance(2 * time.Minute)
+	if _, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, nil
+	}); err != nil {
+		t.Fatalf("expected probe to be admitted, got %v", err)
+	}
+	if s := cb.Snapshot(); s.State != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, s.State)
+	}
+}
+
+// TestStaleProbeFailure verifies a failing probe admitted by an earlier half-open period does not count
+// towards the consecutive failures of the closed state.
+func TestStaleProbeFailure(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	cb := New(WithMaxFailures(2), WithTimeout(time.Minute), WithClock(fc))
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+	for range 2 {
+		_, _ = Execute(context.Background(), cb, failing)
+	}
+	fc.Advance(2 * time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 0, errors.New("failing")
+		})
+	}()
+	<-started
+	cb.Reset()
+	close(release)
+	<-done
+
+	if s := cb.Snapshot(); s.State != StateClosed || s.ConsecutiveFailures != 0 || s.Failures != 3 {
+		t.Fatalf("expected closed state without consecutive failures and 3 failures in total, got %+v", s)
+	}
+	_, _ = Execute(context.Background(), cb, failing)
+	if s := cb.Snapshot(); s.State != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, s.State)
+	}
+}
@@ -0,0 +1,116 @@
+package circuitbreaker
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+const (
+
+	// DefaultMaxFailures is the number of consecutive failures opening a CircuitBreaker created by New.
+	DefaultMaxFailures = 5
+
+	// DefaultTimeout is the duration a CircuitBreaker created by New stays open.
+	DefaultTimeout = 60 * time.Second
+)
+
+// Option configures a CircuitBreaker created by New.
+type Option func(cb *CircuitBreaker)
+
+// New initializes and returns a new CircuitBreaker configured by opts. Without options it opens after
+// DefaultMaxFailures consecutive failures and stays open for DefaultTimeout.
+func New(opts ...Option) *CircuitBreaker {
+	cb := NewCircuitBreaker(DefaultMaxFailures, DefaultTimeout, nil)
+	for _, opt := range opts {
+		opt(cb)
+	}
+	cb.lastTransition = cb.clock.Now()
+	return cb
+}
+
+// WithMaxFailures sets the number of consecutive failures opening the circuit.
+func WithMaxFailures(maxFailures int) Option {
+	return func(cb *CircuitBreaker) {
+		cb.maxFailures = maxFailures
+	}
+}
+
+// WithTimeout sets the duration the circuit stays open before moving to half-open.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cb *CircuitBreaker) {
+		cb.timeout = timeout
+	}
+}
+
+// WithOpenBackoff lets the open duration grow with each consecutive failed half-open probe following the
+// schedule of p, e.g. BaseDelay 5s, Multiplier 2 and MaxDelay 10m. The first opening waits p.Backoff(1),
+// closing the circuit starts over. Jitter is not applied. It replaces the timeout set by WithTimeout.
+func WithOpenBackoff(p *reuse.RetryPolicy) Option {
+	return func(cb *CircuitBreaker) {
+		cb.openBackoff = p
+	}
+}
+
+// WithIsFailure sets a function deciding whether an error counts as failure. Errors for which it returns
+// false, e.g. context cancellations or not found errors, count neither as success nor as failure: they
+// do not reset the consecutive failures and do not close a half-open circuit.
+func WithIsFailure(isFailure func(err error) bool) Option {
+	return func(cb *CircuitBreaker) {
+		cb.isFailure = isFailure
+	}
+}
+
+// WithClock sets the clock used to track failures and timeouts, nil means reuse.SystemClock.
+func WithClock(clock reuse.Clock) Option {
+	return func(cb *CircuitBreaker) {
+		if clock == nil {
+			clock = reuse.SystemClock{}
+		}
+		cb.clock = clock
+	}
+}
+
+// WithName sets a name identifying the CircuitBreaker in logs and snapshots.
+func WithName(name string) Option {
+	return func(cb *CircuitBreaker) {
+		cb.name = name
+	}
+}
+
+// WithWorkFunc sets the function executed by Call.
+func WithWorkFunc(fn WorkFunc) Option {
+	return func(cb *CircuitBreaker) {
+		cb.fn = fn
+	}
+}
+
+// WithHalfOpenLimits limits concurrent probes in the half-open state, see SetHalfOpenLimits.
+func WithHalfOpenLimits(maxProbes, requiredSuccesses int) Option {
+	return func(cb *CircuitBreaker) {
+		cb.maxHalfOpenProbes = maxProbes
+		cb.requiredSuccesses = max(requiredSuccesses, 1)
+	}
+}
+
+// WithSlidingWindow trips the circuit on failure rates within sw instead of consecutive failures.
+func WithSlidingWindow(sw SlidingWindow) Option {
+	return func(cb *CircuitBreaker) {
+		cb.window = newWindow(sw)
+	}
+}
+
+// WithLogger sets a logger receiving failed calls and state transitions, see SetLogger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(cb *CircuitBreaker) {
+		cb.logger = logger
+	}
+}
+
+// WithStateChange registers a function called after each state transition, see OnStateChange.
+func WithStateChange(fn func(name, from, to string)) Option {
+	return func(cb *CircuitBreaker) {
+		cb.onStateChange = fn
+	}
+}
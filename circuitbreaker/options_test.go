@@ -0,0 +1,128 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// TestWithIsFailure verifies errors rejected by the failure filter do not open the circuit.
+func TestWithIsFailure(t *testing.T) {
+	notFound := errors.New("not found")
+	cb := New(
+		WithName("users"),
+		WithMaxFailures(2),
+		WithTimeout(time.Minute),
+		WithIsFailure(func(err error) bool {
+			return !errors.Is(err, notFound) && !errors.Is(err, context.Canceled)
+		}),
+	)
+
+	for range 3 {
+		_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+			return 0, notFound
+		})
+	}
+	if s := cb.Snapshot(); s.State != StateClosed || s.Failures != 0 {
+		t.Fatalf("expected closed state without failures, got %+v", s)
+	}
+
+	for range 2 {
+		_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+			return 0, errors.New("connection refused")
+		})
+	}
+	s := cb.Snapshot()
+	if s.State != StateOpen {
+		t.Fatalf("expected state %s, got %s", StateOpen, s.State)
+	}
+	if s.Name != "users" {
+		t.Fatalf("expected name 'users', got %q", s.Name)
+	}
+}
+
+// TestWithOpenBackoff verifies the open duration grows with failed probes and starts over after closing.
+func TestWithOpenBackoff(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	cb := New(
+		WithMaxFailures(1),
+		WithClock(fc),
+		WithOpenBackoff(&reuse.RetryPolicy{BaseDelay: time.Second, Multiplier: 2, MaxDelay: 4 * time.Second}),
+	)
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+	succeeding := func(context.Context) (int, error) {
+		return 0, nil
+	}
+	// openedFor asserts the circuit rejects calls for just under d and lets a probe pass after d.
+	openedFor := func(d time.Duration, probe func(context.Context) (int, error)) {
+		t.Helper()
+		fc.Advance(d - time.Millisecond)
+		if _, err := Execute(context.Background(), cb, succeeding); !errors.Is(err, ErrOpenState) {
+			t.Fatalf("expected circuit to be open before %v, got %v", d, err)
+		}
+		fc.Advance(2 * time.Millisecond)
+		_, _ = Execute(context.Background(), cb, probe)
+	}
+
+	_, _ = Execute(context.Background(), cb, failing)
+	openedFor(time.Second, failing)
+	openedFor(2*time.Second, failing)
+	openedFor(4*time.Second, failing)
+	openedFor(4*time.Second, succeeding)
+	if s := cb.Snapshot(); s.State != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, s.State)
+	}
+
+	_, _ = Execute(context.Background(), cb, failing)
+	openedFor(time.Second, succeeding)
+}
+
+// TestWithIsFailureIgnored verifies errors rejected by the failure filter neither reset the consecutive
+// failures nor close a half-open circuit.
+func TestWithIsFailureIgnored(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	cb := New(
+		WithMaxFailures(2),
+		WithTimeout(time.Minute),
+		WithClock(fc),
+		WithHalfOpenLimits(1, 1),
+		WithIsFailure(func(err error) bool {
+			return !errors.Is(err, context.Canceled)
+		}),
+	)
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+	canceled := func(context.Context) (int, error) {
+		return 0, context.Canceled
+	}
+
+	_, _ = Execute(context.Background(), cb, failing)
+	_, _ = Execute(context.Background(), cb, canceled)
+	if s := cb.Snapshot(); s.ConsecutiveFailures != 1 || s.Successes != 0 {
+		t.Fatalf("expected 1 consecutive failure and no success, got %+v", s)
+	}
+	_, _ = Execute(context.Background(), cb, failing)
+	if s := cb.Snapshot(); s.State != StateOpen {
+		t.Fatalf("expected state %s, got %s", StateOpen, s.State)
+	}
+
+	fc.Advance(2 * time.Minute)
+	_, _ = Execute(context.Background(), cb, canceled)
+	if s := cb.Snapshot(); s.State != StateHalfOpen {
+		t.Fatalf("expected state %s, got %s", StateHalfOpen, s.State)
+	}
+	if _, err := Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, nil
+	}); err != nil {
+		t.Fatalf("expected probe to be admitted, got %v", err)
+	}
+	if s := cb.Snapshot(); s.State != StateClosed {
+		t.Fatalf("expected state %s, got %s", StateClosed, s.State)
+	}
+}
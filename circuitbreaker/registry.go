@@ -0,0 +1,80 @@
+package circuitbreaker
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Registry holds one CircuitBreaker per name, e.g. per downstream host, created lazily from shared options.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+
+	// mu protects breakers.
+	mu sync.Mutex
+
+	// breakers holds the breakers created so far by name.
+	breakers map[string]*CircuitBreaker
+
+	// opts are applied to every CircuitBreaker created.
+	opts []Option
+}
+
+// NewRegistry returns an empty Registry creating breakers with opts. WithName is applied after opts.
+func NewRegistry(opts ...Option) *Registry {
+	return &Registry{
+		breakers: make(map[string]*CircuitBreaker),
+		opts:     opts,
+	}
+}
+
+// Get returns the CircuitBreaker for name, creating it on first use.
+func (r *Registry) Get(name string) *CircuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cb, ok := r.breakers[name]; ok {
+		return cb
+	}
+	cb := New(append(slices.Clone(r.opts), WithName(name))...)
+	r.breakers[name] = cb
+	return cb
+}
+
+// Names returns the names of all breakers created so far in ascending order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Sorted(maps.Keys(r.breakers))
+}
+
+// Snapshots returns the snapshots of all breakers created so far ordered by name.
+func (r *Registry) Snapshots() []Snapshot {
+	r.mu.Lock()
+	breakers := make([]*CircuitBreaker, 0, len(r.breakers))
+	for _, name := range slices.Sorted(maps.Keys(r.breakers)) {
+		breakers = append(breakers, r.breakers[name])
+	}
+	r.mu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(breakers))
+	for _, cb := range breakers {
+		snapshots = append(snapshots, cb.Snapshot())
+	}
+	return snapshots
+}
+
+// Reset closes the circuit of the CircuitBreaker for name, see CircuitBreaker.Reset.
+func (r *Registry) Reset(name string) {
+	r.Get(name).Reset()
+}
+
+// ForceOpen opens the circuit of the CircuitBreaker for name, creating it if necessary, see CircuitBreaker.ForceOpen.
+func (r *Registry) ForceOpen(name string) {
+	r.Get(name).ForceOpen()
+}
+
+// ForceClosed closes the circuit of the CircuitBreaker for name, creating it if necessary, see CircuitBreaker.ForceClosed.
+func (r *Registry) ForceClosed(name string) {
+	r.Get(name).ForceClosed()
+}
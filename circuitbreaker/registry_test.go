@@ -0,0 +1,42 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestRegistry verifies breakers are created once per name and can be controlled by name.
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(WithMaxFailures(1), WithTimeout(time.Minute))
+
+	a := r.Get("a.example.com")
+	if r.Get("a.example.com") != a {
+		t.Fatal("expected the same breaker for the same name")
+	}
+	_, _ = Execute(context.Background(), r.Get("b.example.com"), func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	})
+
+	if names := r.Names(); !reflect.DeepEqual(names, []string{"a.example.com", "b.example.com"}) {
+		t.Fatalf("unexpected names %v", names)
+	}
+	snapshots := r.Snapshots()
+	if snapshots[0].State != StateClosed || snapshots[1].State != StateOpen {
+		t.Fatalf("unexpected snapshots %+v", snapshots)
+	}
+	if snapshots[1].Name != "b.example.com" {
+		t.Fatalf("expected name b.example.com, got %q", snapshots[1].Name)
+	}
+
+	r.Reset("b.example.com")
+	r.ForceOpen("a.example.com")
+	if a.Snapshot().State != StateForcedOpen {
+		t.Fatalf("expected forced state %s, got %s", StateForcedOpen, a.Snapshot().State)
+	}
+	if s := r.Get("b.example.com").Snapshot(); s.State != StateClosed || s.ConsecutiveFailures != 0 {
+		t.Fatalf("expected reset breaker, got %+v", s)
+	}
+}
@@ -0,0 +1,55 @@
+package circuitbreaker
+
+import "time"
+
+// Snapshot is a point in time view of the state and counters of a CircuitBreaker, e.g. to export metrics.
+type Snapshot struct {
+
+	// Name is the name of the CircuitBreaker, empty if not set.
+	Name string
+
+	// State is the current state of the CircuitBreaker.
+	State string
+
+	// ConsecutiveFailures is the number of failures since the last success.
+	ConsecutiveFailures int
+
+	// Calls is the number of calls executed, including calls with errors not counting as failure.
+	Calls uint64
+
+	// Successes is the number of calls executed successfully.
+	Successes uint64
+
+	// Failures is the number of calls that failed.
+	Failures uint64
+
+	// Rejected is the number of calls short-circuited without being executed.
+	Rejected uint64
+
+	// FallbackSuccesses is the number of fallbacks returning a result instead of a failed or rejected call.
+	FallbackSuccesses uint64
+
+	// FallbackFailures is the number of fallbacks returning an error.
+	FallbackFailures uint64
+
+	// LastTransition is the time of the last state transition, or the creation time if there was none.
+	LastTransition time.Time
+}
+
+// Snapshot returns the current state and counters of the CircuitBreaker.
+func (cb *CircuitBreaker) Snapshot() Snapshot {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return Snapshot{
+		Name:                cb.name,
+		State:               cb.state,
+		ConsecutiveFailures: cb.failures,
+		Calls:               cb.calls,
+		Successes:           cb.successes,
+		Failures:            cb.totalFailures,
+		Rejected:            cb.rejected,
+		FallbackSuccesses:   cb.fallbackSuccesses,
+		FallbackFailures:    cb.fallbackFailures,
+		LastTransition:      cb.lastTransition,
+	}
+}
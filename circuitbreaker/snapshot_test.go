@@ -0,0 +1,74 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// TestSnapshotAndStateChange verifies state transitions are reported and counted in the snapshot.
+func TestSnapshotAndStateChange(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, nil)
+	fc := reuse.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb.SetClock(fc)
+	if s := cb.Snapshot(); !s.LastTransition.Equal(fc.Now()) {
+		t.Fatalf("expected last transition %v, got %v", fc.Now(), s.LastTransition)
+	}
+	var transitions [][2]string
+	cb.OnStateChange(func(name, from, to string) {
+		transitions = append(transitions, [2]string{from, to})
+		_ = cb.Snapshot()
+	})
+
+	_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	})
+	_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, nil
+	})
+	fc.Advance(2 * time.Minute)
+	_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+		return 0, nil
+	})
+
+	expected := [][2]string{{StateClosed, StateOpen}, {StateOpen, StateHalfOpen}, {StateHalfOpen, StateClosed}}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Fatalf("expected transitions %v, got %v", expected, transitions)
+	}
+	snapshot := cb.Snapshot()
+	expectedSnapshot := Snapshot{
+		State:          StateClosed,
+		Calls:          2,
+		Successes:      1,
+		Failures:       1,
+		Rejected:       1,
+		LastTransition: fc.Now(),
+	}
+	if snapshot != expectedSnapshot {
+		t.Fatalf("expected %+v, got %+v", expectedSnapshot, snapshot)
+	}
+}
+
+// TestStateChangeName verifies a callback shared by several breakers receives the name of the breaker changing state.
+func TestStateChangeName(t *testing.T) {
+	var names []string
+	onStateChange := func(name, from, to string) {
+		names = append(names, name)
+	}
+	users := New(WithName("users"), WithMaxFailures(1), WithStateChange(onStateChange))
+	orders := New(WithName("orders"), WithMaxFailures(1), WithStateChange(onStateChange))
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("failing")
+	}
+
+	_, _ = Execute(context.Background(), orders, failing)
+	_, _ = Execute(context.Background(), users, failing)
+
+	if expected := []string{"orders", "users"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected names %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,83 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Transport is a http.RoundTripper routing each request through the CircuitBreaker of its host, taken
+// from a Registry. Transport errors and failure status codes count as failures. While a circuit is open,
+// requests fail with an error wrapping ErrOpenState without touching the network.
+//
+// Client side cancellations count as failures as well, use WithIsFailure on the Registry to exclude them.
+type Transport struct {
+
+	// Base executes the requests, nil means http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Registry provides the breakers by host, nil means a registry created with default options on first use.
+	Registry *Registry
+
+	// IsFailureStatus decides whether a status code counts as failure, nil counts all 5xx codes.
+	IsFailureStatus func(statusCode int) bool
+
+	// once guards creating the default registry.
+	once sync.Once
+}
+
+// failureStatusError signals a response with a failure status code to the CircuitBreaker.
+type failureStatusError struct {
+
+	// resp is the response received.
+	resp *http.Response
+}
+
+// Error returns the status of the response
+func (e *failureStatusError) Error() string {
+	return fmt.Sprintf("failure status %s", e.resp.Status)
+}
+
+// RoundTrip executes the request through the CircuitBreaker of its host. Responses with a failure status
+// code are returned as is after being recorded.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t.once.Do(func() {
+		if t.Registry == nil {
+			t.Registry = NewRegistry()
+		}
+	})
+	isFailureStatus := t.IsFailureStatus
+	if isFailureStatus == nil {
+		isFailureStatus = func(statusCode int) bool {
+			return statusCode >= http.StatusInternalServerError
+		}
+	}
+
+	resp, err := Execute(req.Context(), t.Registry.Get(req.URL.Host), func(context.Context) (*http.Response, error) {
+		resp, err := base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if isFailureStatus(resp.StatusCode) {
+			return nil, &failureStatusError{resp: resp}
+		}
+		return resp, nil
+	})
+	var statusErr *failureStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.resp, nil
+	}
+	if errors.Is(err, ErrOpenState) {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%s: %w", req.URL.Host, err)
+	}
+	return resp, err
+}
@@ -0,0 +1,67 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTransport verifies failure status codes open the circuit of the host and open circuits skip the network.
+func TestTransport(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	registry := NewRegistry(WithMaxFailures(2), WithTimeout(time.Minute))
+	client := &http.Client{Transport: &Transport{Registry: registry}}
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("expected status 500, got %d", resp.StatusCode)
+		}
+	}
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrOpenState) {
+		t.Fatalf("expected ErrOpenState, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 calls to reach the server, got %d", calls.Load())
+	}
+	snapshots := registry.Snapshots()
+	if len(snapshots) != 1 || snapshots[0].State != StateOpen {
+		t.Fatalf("expected one open breaker, got %+v", snapshots)
+	}
+}
+
+// TestTransportSuccess verifies successful responses keep the circuit closed.
+func TestTransportSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	transport := &Transport{}
+	client := &http.Client{Transport: transport}
+	for range DefaultMaxFailures + 1 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	if s := transport.Registry.Snapshots()[0]; s.State != StateClosed || s.Successes != DefaultMaxFailures+1 {
+		t.Fatalf("expected closed breaker with successes only, got %+v", s)
+	}
+}
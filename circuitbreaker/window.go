@@ -0,0 +1,130 @@
+package circuitbreaker
+
+import (
+	"time"
+)
+
+// WindowType selects how a SlidingWindow aggregates calls.
+type WindowType int
+
+const (
+
+	// CountBasedWindow aggregates the outcome of the last Size calls.
+	CountBasedWindow WindowType = iota
+
+	// TimeBasedWindow aggregates the outcome of the calls within the last Size seconds.
+	TimeBasedWindow
+)
+
+// SlidingWindow configures a CircuitBreaker to trip on the failure rate or slow call rate of recent
+// calls instead of consecutive failures, modelled on resilience4j.
+type SlidingWindow struct {
+
+	// Type selects a count or time based window.
+	Type WindowType
+
+	// Size is the number of calls (CountBasedWindow) or seconds (TimeBasedWindow) aggregated.
+	Size int
+
+	// MinimumCalls is the number of calls within the window required before the rates are evaluated.
+	MinimumCalls int
+
+	// FailureRateThreshold is the percentage of failed calls at which the circuit opens, zero means 50.
+	FailureRateThreshold float64
+
+	// SlowCallDuration is the duration above which a call is considered slow, zero disables slow call tracking.
+	SlowCallDuration time.Duration
+
+	// SlowCallRateThreshold is the percentage of slow calls at which the circuit opens, zero disables slow call tracking.
+	SlowCallRateThreshold float64
+}
+
+// windowBucket aggregates the outcome of one or more calls.
+type windowBucket struct {
+
+	// epoch identifies the second a bucket of a time based window belongs to.
+	epoch int64
+
+	// calls is the number of calls.
+	calls int
+
+	// failures is the number of failed calls.
+	failures int
+
+	// slow is the number of slow calls.
+	slow int
+}
+
+// window records call outcomes for a SlidingWindow. It is not safe for concurrent use.
+type window struct {
+
+	// config is the configuration of the window.
+	config SlidingWindow
+
+	// buckets holds one bucket per call (count based) or second (time based).
+	buckets []windowBucket
+
+	// next is the bucket written next by a count based window.
+	next int
+}
+
+// newWindow returns an empty window for config.
+func newWindow(config SlidingWindow) *window {
+	if config.FailureRateThreshold <= 0 {
+		config.FailureRateThreshold = 50
+	}
+	config.Size = max(config.Size, 1)
+	return &window{config: config, buckets: make([]windowBucket, config.Size)}
+}
+
+// record adds the outcome of a call finished at now.
+func (w *window) record(now time.Time, failed bool, duration time.Duration) {
+	var bucket *windowBucket
+	switch w.config.Type {
+	case TimeBasedWindow:
+		epoch := now.Unix()
+		bucket = &w.buckets[epoch%int64(len(w.buckets))]
+		if bucket.epoch != epoch {
+			*bucket = windowBucket{epoch: epoch}
+		}
+	default:
+		bucket = &w.buckets[w.next]
+		*bucket = windowBucket{}
+		w.next = (w.next + 1) % len(w.buckets)
+	}
+	bucket.calls++
+	if failed {
+		bucket.failures++
+	}
+	if w.config.SlowCallDuration > 0 && duration > w.config.SlowCallDuration {
+		bucket.slow++
+	}
+}
+
+// exceeded reports whether the failure or slow call rate at now reached its threshold.
+func (w *window) exceeded(now time.Time) bool {
+	var total windowBucket
+	epoch := now.Unix()
+	for _, bucket := range w.buckets {
+		if w.config.Type == TimeBasedWindow && epoch-bucket.epoch >= int64(len(w.buckets)) {
+			continue
+		}
+		total.calls += bucket.calls
+		total.failures += bucket.failures
+		total.slow += bucket.slow
+	}
+	if total.calls == 0 || total.calls < w.config.MinimumCalls {
+		return false
+	}
+	if 100*float64(total.failures)/float64(total.calls) >= w.config.FailureRateThreshold {
+		return true
+	}
+	return w.config.SlowCallDuration > 0 && w.config.SlowCallRateThreshold > 0 &&
+		100*float64(total.slow)/float64(total.calls) >= w.config.SlowCallRateThreshold
+}
+
+// reset removes all recorded outcomes.
+func (w *window) reset() {
+	clear(w.buckets)
+	w.next = 0
+}
@@ -0,0 +1,61 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// TestSlidingWindowCountBased verifies a count based window trips on the failure rate once the minimum calls are reached.
+func TestSlidingWindowCountBased(t *testing.T) {
+	cb := NewSlidingWindowCircuitBreaker(SlidingWindow{
+		Type:                 CountBasedWindow,
+		Size:                 5,
+		MinimumCalls:         5,
+		FailureRateThreshold: 40,
+	}, time.Minute, nil)
+
+	outcomes := []error{nil, errors.New("failing"), nil, nil, errors.New("failing")}
+	for i, outcome := range outcomes {
+		if cb.state != StateClosed {
+			t.Fatalf("expected state %s before call %d, got %s", StateClosed, i, cb.state)
+		}
+		_, _ = Execute(context.Background(), cb, func(context.Context) (int, error) {
+			return 0, outcome
+		})
+	}
+	if cb.state != StateOpen {
+		t.Fatalf("expected state %s at 40%% failures, got %s", StateOpen, cb.state)
+	}
+}
+
+// TestSlidingWindowTimeBased verifies a time based window trips on slow calls and forgets calls outside the window.
+func TestSlidingWindowTimeBased(t *testing.T) {
+	cb := NewSlidingWindowCircuitBreaker(SlidingWindow{
+		Type:                  TimeBasedWindow,
+		Size:                  10,
+		MinimumCalls:          2,
+		SlowCallDuration:      time.Second,
+		SlowCallRateThreshold: 100,
+	}, time.Minute, nil)
+	fc := reuse.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb.SetClock(fc)
+
+	slow := func(context.Context) (int, error) {
+		fc.Advance(2 * time.Second)
+		return 0, nil
+	}
+	_, _ = Execute(context.Background(), cb, slow)
+	fc.Advance(time.Minute)
+	_, _ = Execute(context.Background(), cb, slow)
+	if cb.state != StateClosed {
+		t.Fatalf("expected state %s with expired calls, got %s", StateClosed, cb.state)
+	}
+	_, _ = Execute(context.Background(), cb, slow)
+	if cb.state != StateOpen {
+		t.Fatalf("expected state %s with slow calls, got %s", StateOpen, cb.state)
+	}
+}
@@ -0,0 +1,152 @@
+package reuse
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts reading the current time and waiting, so time dependent code can be tested deterministically.
+type Clock interface {
+
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a Clock backed by the time package
+type SystemClock struct{}
+
+// Now returns time.Now()
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns time.Since(t)
+func (SystemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After returns time.After(d)
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrSystem returns c or SystemClock if c is nil.
+func clockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock{}
+	}
+	return c
+}
+
+// fakeTimer is a pending After call of a FakeClock.
+type fakeTimer struct {
+
+	// deadline is the time at which the timer fires.
+	deadline time.Time
+
+	// c receives the time of the clock when the timer fires.
+	c chan time.Time
+}
+
+// FakeClock is a Clock only moving when told to. Timers created with After fire once the clock
+// is advanced or set to or beyond their deadline.
+type FakeClock struct {
+
+	// mu protects now and timers.
+	mu sync.Mutex
+
+	// cond is signaled whenever a timer is added.
+	cond *sync.Cond
+
+	// now is the current time of the clock.
+	now time.Time
+
+	// timers holds all timers not fired yet.
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	fc := &FakeClock{now: now}
+	fc.cond = sync.NewCond(&fc.mu)
+	return fc
+}
+
+// Now returns the current time of the clock.
+func (fc *FakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+// Since returns the time elapsed on the clock since t.
+func (fc *FakeClock) Since(t time.Time) time.Duration {
+	return fc.Now().Sub(t)
+}
+
+// After returns a channel receiving the time of the clock once it has been moved forward by d.
+// A non-positive d fires immediately.
+func (fc *FakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- fc.now
+		return c
+	}
+	fc.timers = append(fc.timers, &fakeTimer{deadline: fc.now.Add(d), c: c})
+	fc.cond.Broadcast()
+	return c
+}
+
+// Advance moves the clock forward by d and fires all timers due.
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.setLocked(fc.now.Add(d))
+}
+
+// Set moves the clock to t and fires all timers due. Setting the clock back does not fire timers.
+func (fc *FakeClock) Set(t time.Time) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.setLocked(t)
+}
+
+// setLocked sets now and fires all timers due, fc.mu must be held.
+func (fc *FakeClock) setLocked(t time.Time) {
+	fc.now = t
+	pending := fc.timers[:0]
+	for _, timer := range fc.timers {
+		if timer.deadline.After(t) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- t
+	}
+	clear(fc.timers[len(pending):])
+	fc.timers = pending
+}
+
+// PendingTimers returns the number of timers not fired yet.
+func (fc *FakeClock) PendingTimers() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return len(fc.timers)
+}
+
+// BlockUntil blocks until at least n timers are pending. It allows a test to wait for code
+// running in another goroutine to start waiting before advancing the clock.
+func (fc *FakeClock) BlockUntil(n int) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for len(fc.timers) < n {
+		fc.cond.Wait()
+	}
+}
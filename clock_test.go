@@ -0,0 +1,74 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFakeClockAfter verifies timers of a FakeClock only fire once the clock reaches their deadline.
+func TestFakeClockAfter(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeClock(start)
+
+	c := fc.After(time.Minute)
+	if fc.PendingTimers() != 1 {
+		t.Fatalf("expected 1 pending timer, got %d", fc.PendingTimers())
+	}
+	fc.Advance(30 * time.Second)
+	select {
+	case <-c:
+		t.Fatal("timer fired early")
+	default:
+	}
+	fc.Advance(30 * time.Second)
+	select {
+	case fired := <-c:
+		if !fired.Equal(start.Add(time.Minute)) {
+			t.Fatalf("expected %v, got %v", start.Add(time.Minute), fired)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if fc.PendingTimers() != 0 {
+		t.Fatalf("expected no pending timers, got %d", fc.PendingTimers())
+	}
+	if fc.Since(start) != time.Minute {
+		t.Fatalf("expected a minute since start, got %v", fc.Since(start))
+	}
+}
+
+// TestRetryPolicyFakeClock verifies a RetryPolicy waits on its clock instead of real time.
+func TestRetryPolicyFakeClock(t *testing.T) {
+	fc := NewFakeClock(time.Now())
+	p := &RetryPolicy{BaseDelay: time.Hour, MaxAttempts: 2, Clock: fc}
+
+	done := make(chan error)
+	go func() {
+		done <- p.Do(context.Background(), func(context.Context) error {
+			return errors.New("failing")
+		})
+	}()
+	fc.BlockUntil(1)
+	fc.Advance(time.Hour)
+
+	var retryErr *RetryError
+	if err := <-done; !errors.As(err, &retryErr) {
+		t.Fatalf("expected *RetryError, got %v", err)
+	}
+	if retryErr.Elapsed != time.Hour {
+		t.Fatalf("expected an hour elapsed, got %v", retryErr.Elapsed)
+	}
+}
+
+// TestCalendar verifies a Calendar formats the time of its clock.
+func TestCalendar(t *testing.T) {
+	c := Calendar{Clock: NewFakeClock(time.Date(2023, 1, 1, 1, 1, 1, 0, time.UTC))}
+	if d := c.CurrentDate(); d != "2023-01-01" {
+		t.Fatalf("expected 2023-01-01, got %s", d)
+	}
+	if d := c.UTCCurrentDateTime(); d != "2023-01-01T01:01:01Z" {
+		t.Fatalf("expected 2023-01-01T01:01:01Z, got %s", d)
+	}
+}
@@ -4,6 +4,19 @@ import (
 	"time"
 )
 
+// Calendar formats the current time of its Clock. The Current* and UTCCurrent* functions use a
+// Calendar with SystemClock, tests can pass a FakeClock to get deterministic dates.
+type Calendar struct {
+
+	// Clock provides the current time, nil means SystemClock.
+	Clock Clock
+}
+
+// now returns the current time of the clock of the Calendar.
+func (c Calendar) now() time.Time {
+	return clockOrSystem(c.Clock).Now()
+}
+
 // Date returns a string representation of the date in format YYYY-MM-DD
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func Date(t time.Time) string {
@@ -25,35 +38,71 @@ func DateTimeWithTimeZone(t time.Time) string {
 // CurrentDate returns a string representation of the current date in format YYYY-MM-DD
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func CurrentDate() string {
-	return Date(time.Now())
+	return Calendar{}.CurrentDate()
+}
+
+// CurrentDate returns a string representation of the current date of c.Clock in format YYYY-MM-DD
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) CurrentDate() string {
+	return Date(c.now())
 }
 
 // CurrentDateTime returns a string representation of the current date and time in format YYYY-MM-DDTHH:MM:SSZ
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func CurrentDateTime() string {
-	return DateTime(time.Now())
+	return Calendar{}.CurrentDateTime()
+}
+
+// CurrentDateTime returns a string representation of the current date and time of c.Clock in format YYYY-MM-DDTHH:MM:SSZ
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) CurrentDateTime() string {
+	return DateTime(c.now())
 }
 
 // CurrentDateTimeWithTimeZone returns a string representation of the current date and time in format YYYY-MM-DDTHH:MM:SSZ07:00
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func CurrentDateTimeWithTimeZone() string {
-	return DateTimeWithTimeZone(time.Now())
+	return Calendar{}.CurrentDateTimeWithTimeZone()
+}
+
+// CurrentDateTimeWithTimeZone returns a string representation of the current date and time of c.Clock in format YYYY-MM-DDTHH:MM:SSZ07:00
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) CurrentDateTimeWithTimeZone() string {
+	return DateTimeWithTimeZone(c.now())
 }
 
 // UTCCurrentDateTimeWithTimeZone returns a string representation of the current date and time (UTC) in format YYYY-MM-DDTHH:MM:SSZ
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func UTCCurrentDateTimeWithTimeZone() string {
-	return DateTimeWithTimeZone(time.Now().UTC())
+	return Calendar{}.UTCCurrentDateTimeWithTimeZone()
+}
+
+// UTCCurrentDateTimeWithTimeZone returns a string representation of the current date and time (UTC) of c.Clock in format YYYY-MM-DDTHH:MM:SSZ
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) UTCCurrentDateTimeWithTimeZone() string {
+	return DateTimeWithTimeZone(c.now().UTC())
 }
 
 // UTCCurrentDate returns a string representation of the current date (UTC) in format YYYY-MM-DD
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func UTCCurrentDate() string {
-	return Date(time.Now().UTC())
+	return Calendar{}.UTCCurrentDate()
+}
+
+// UTCCurrentDate returns a string representation of the current date (UTC) of c.Clock in format YYYY-MM-DD
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) UTCCurrentDate() string {
+	return Date(c.now().UTC())
 }
 
 // UTCCurrentDateTime returns a string representation of the current date and time (UTC) in format YYYY-MM-DDTHH:MM:SSZ
 // It is RFC 3339, ISO-8601 and W3C(HTML) compliant
 func UTCCurrentDateTime() string {
-	return DateTime(time.Now().UTC())
+	return Calendar{}.UTCCurrentDateTime()
+}
+
+// UTCCurrentDateTime returns a string representation of the current date and time (UTC) of c.Clock in format YYYY-MM-DDTHH:MM:SSZ
+// It is RFC 3339, ISO-8601 and W3C(HTML) compliant
+func (c Calendar) UTCCurrentDateTime() string {
+	return DateTime(c.now().UTC())
 }
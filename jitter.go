@@ -0,0 +1,112 @@
+package reuse
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// JitterStrategy randomizes the delay waited before the next attempt.
+//
+// base is the BaseDelay of the policy, delay the capped exponential delay computed for the
+// upcoming wait and previous the delay actually waited before the last attempt (zero on the
+// first wait). Strategies only needing delay may ignore the other values.
+type JitterStrategy func(base, delay, previous time.Duration) time.Duration
+
+// RandomSource provides the random numbers used by jitter strategies. *rand.Rand satisfies it,
+// so tests can inject a seeded source to assert an exact schedule. The jitter strategies guard
+// the source with a mutex, as a policy may be shared across goroutines and *rand.Rand is not
+// safe for concurrent use.
+type RandomSource interface {
+
+	// Float64 returns a pseudo-random number in [0.0,1.0).
+	Float64() float64
+}
+
+// globalSource is the RandomSource used when nil is passed to a jitter strategy.
+type globalSource struct{}
+
+// Float64 returns rand.Float64()
+func (globalSource) Float64() float64 {
+	return rand.Float64()
+}
+
+// lockedSource serializes access to a RandomSource.
+type lockedSource struct {
+
+	// mu guards src.
+	mu sync.Mutex
+
+	// src is the guarded source.
+	src RandomSource
+}
+
+// Float64 returns src.Float64() while holding mu
+func (l *lockedSource) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.src.Float64()
+}
+
+// sourceOrGlobal returns src guarded by a mutex, or the global source of math/rand/v2 if src is nil.
+func sourceOrGlobal(src RandomSource) RandomSource {
+	if src == nil {
+		return globalSource{}
+	}
+	return &lockedSource{src: src}
+}
+
+// between returns a random duration in [low, high).
+func between(src RandomSource, low, high time.Duration) time.Duration {
+	return low + multiplyDuration(high-low, src.Float64())
+}
+
+// NoJitter waits exactly the computed delay.
+func NoJitter(_, delay, _ time.Duration) time.Duration {
+	return delay
+}
+
+// ProportionalJitter returns a JitterStrategy scaling the computed delay by a random
+// factor in [1-fraction, 1+fraction). ProportionalJitter(0.25, nil) yields ±25%.
+// A nil src uses the global source of math/rand/v2.
+func ProportionalJitter(fraction float64, src RandomSource) JitterStrategy {
+	src = sourceOrGlobal(src)
+	return func(_, delay, _ time.Duration) time.Duration {
+		return multiplyDuration(delay, 1-fraction+src.Float64()*2*fraction)
+	}
+}
+
+// FullJitter returns a JitterStrategy waiting a random delay in [0, delay).
+// A nil src uses the global source of math/rand/v2.
+func FullJitter(src RandomSource) JitterStrategy {
+	src = sourceOrGlobal(src)
+	return func(_, delay, _ time.Duration) time.Duration {
+		return between(src, 0, delay)
+	}
+}
+
+// EqualJitter returns a JitterStrategy waiting half the delay plus a random delay in [0, delay/2).
+// A nil src uses the global source of math/rand/v2.
+func EqualJitter(src RandomSource) JitterStrategy {
+	src = sourceOrGlobal(src)
+	return func(_, delay, _ time.Duration) time.Duration {
+		return between(src, delay/2, delay)
+	}
+}
+
+// DecorrelatedJitter returns a JitterStrategy waiting a random delay in [base, 3*previous),
+// ignoring the exponential schedule. The first wait uses base as previous delay. If the policy
+// has no BaseDelay the computed delay is used instead. Use MaxDelay to cap the growth.
+// A nil src uses the global source of math/rand/v2.
+func DecorrelatedJitter(src RandomSource) JitterStrategy {
+	src = sourceOrGlobal(src)
+	return func(base, delay, previous time.Duration) time.Duration {
+		if base <= 0 {
+			base = delay
+		}
+		if previous < base {
+			previous = base
+		}
+		return between(src, base, 3*previous)
+	}
+}
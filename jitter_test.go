@@ -0,0 +1,83 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fixedSource is a RandomSource always returning the same value.
+type fixedSource float64
+
+// Float64 returns the fixed value
+func (f fixedSource) Float64() float64 {
+	return float64(f)
+}
+
+// TestJitterStrategies verifies the delays computed by the jitter strategies for a fixed random value.
+func TestJitterStrategies(t *testing.T) {
+	src := fixedSource(0.5)
+	tests := []struct {
+		name     string
+		jitter   JitterStrategy
+		previous time.Duration
+		expected time.Duration
+	}{
+		{"none", NoJitter, 0, 8 * time.Second},
+		{"proportional", ProportionalJitter(0.25, src), 0, 8 * time.Second},
+		{"full", FullJitter(src), 0, 4 * time.Second},
+		{"equal", EqualJitter(src), 0, 6 * time.Second},
+		{"decorrelated first", DecorrelatedJitter(src), 0, 2 * time.Second},
+		{"decorrelated", DecorrelatedJitter(src), 3 * time.Second, 5 * time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if d := test.jitter(time.Second, 8*time.Second, test.previous); d != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, d)
+			}
+		})
+	}
+}
+
+// TestRetryPolicyDecorrelatedJitter verifies the schedule of a policy using decorrelated jitter capped by MaxDelay.
+func TestRetryPolicyDecorrelatedJitter(t *testing.T) {
+	var seen []time.Duration
+	p := &RetryPolicy{
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    4 * time.Millisecond,
+		MaxAttempts: 4,
+		Jitter:      DecorrelatedJitter(fixedSource(0.5)),
+		OnRetry: func(_ int, _ error, delay time.Duration) {
+			seen = append(seen, delay)
+		},
+	}
+	_ = p.Do(context.Background(), func(context.Context) error {
+		return errors.New("failing")
+	})
+	expected := []time.Duration{2 * time.Millisecond, 3500 * time.Microsecond, 4 * time.Millisecond}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+}
+
+// TestJitterConcurrentSource verifies a *rand.Rand can be shared by a jitter strategy used from many goroutines.
+func TestJitterConcurrentSource(t *testing.T) {
+	jitter := FullJitter(rand.New(rand.NewPCG(1, 2)))
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if d := jitter(0, time.Second, 0); d < 0 || d >= time.Second {
+					t.Errorf("expected delay in [0, 1s), got %v", d)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
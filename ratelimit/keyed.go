@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// keyedEntry is a Limiter of a Keyed limiter together with its last use.
+type keyedEntry struct {
+
+	// limiter is the Limiter of the key.
+	limiter Limiter
+
+	// lastUsed is the time the limiter was last returned.
+	lastUsed time.Time
+}
+
+// Keyed holds one Limiter per key, e.g. per client or host, created on first use. Limiters not used
+// for the idle TTL are evicted, so a later call starts with a fresh limiter.
+//
+// A Keyed limiter is safe for concurrent use.
+type Keyed[K comparable] struct {
+
+	// mu protects all fields below.
+	mu sync.Mutex
+
+	// limiters holds the limiters by key.
+	limiters map[K]*keyedEntry
+
+	// newLimiter creates the Limiter for a new key.
+	newLimiter func() Limiter
+
+	// idleTTL is the time after which an unused limiter is evicted, zero disables eviction.
+	idleTTL time.Duration
+
+	// lastSweep is the time idle limiters were last evicted.
+	lastSweep time.Time
+
+	// clock provides the current time for eviction.
+	clock reuse.Clock
+}
+
+// NewKeyed returns a Keyed limiter creating limiters with newLimiter and evicting them after idleTTL without use.
+func NewKeyed[K comparable](newLimiter func() Limiter, idleTTL time.Duration) *Keyed[K] {
+	return &Keyed[K]{
+		limiters:   make(map[K]*keyedEntry),
+		newLimiter: newLimiter,
+		idleTTL:    idleTTL,
+		clock:      reuse.SystemClock{},
+	}
+}
+
+// SetClock replaces the clock used for eviction, nil restores reuse.SystemClock. The clocks of the
+// limiters are set by newLimiter.
+func (k *Keyed[K]) SetClock(clock reuse.Clock) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if clock == nil {
+		clock = reuse.SystemClock{}
+	}
+	k.clock = clock
+}
+
+// Get returns the Limiter for key, creating it on first use.
+func (k *Keyed[K]) Get(key K) Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := k.clock.Now()
+	if k.idleTTL > 0 && now.Sub(k.lastSweep) >= k.idleTTL {
+		k.evict(now)
+	}
+	entry, ok := k.limiters[key]
+	if !ok {
+		entry = &keyedEntry{limiter: k.newLimiter()}
+		k.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter
+}
+
+// Allow reports whether a call for key may happen now, see Limiter.Allow.
+func (k *Keyed[K]) Allow(key K) bool {
+	return k.Get(key).Allow()
+}
+
+// Wait blocks until a call for key may happen or the context is done, see Limiter.Wait.
+func (k *Keyed[K]) Wait(ctx context.Context, key K) error {
+	return k.Get(key).Wait(ctx)
+}
+
+// Reserve reserves a call for key, see Limiter.Reserve.
+func (k *Keyed[K]) Reserve(key K) *Reservation {
+	return k.Get(key).Reserve()
+}
+
+// Len returns the number of limiters held.
+func (k *Keyed[K]) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.limiters)
+}
+
+// evict removes the limiters idle for at least idleTTL, k.mu must be held.
+func (k *Keyed[K]) evict(now time.Time) {
+	for key, entry := range k.limiters {
+		if now.Sub(entry.lastUsed) >= k.idleTTL {
+			delete(k.limiters, key)
+		}
+	}
+	k.lastSweep = now
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// ErrLimitExceeded is returned by Wait if the limiter can never allow a call, e.g. with a limit of zero.
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// Limiter limits the rate of calls.
+type Limiter interface {
+
+	// Allow reports whether a call may happen now, consuming capacity if so.
+	Allow() bool
+
+	// Wait blocks until a call may happen or the context is done.
+	Wait(ctx context.Context) error
+
+	// Reserve consumes capacity for a call and returns when it may happen.
+	Reserve() *Reservation
+}
+
+// Reservation is capacity reserved for a call at a point in time.
+type Reservation struct {
+
+	// ok is false if the limiter can never allow the call.
+	ok bool
+
+	// timeToAct is the time at which the call may happen.
+	timeToAct time.Time
+
+	// clock provides the current time to compute the delay.
+	clock reuse.Clock
+
+	// once guards cancel.
+	once sync.Once
+
+	// cancel returns the reserved capacity to the limiter.
+	cancel func()
+}
+
+// OK reports whether the limiter can allow the call at all.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns the time to wait before the call may happen, zero if it may happen now.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	return max(r.timeToAct.Sub(r.clock.Now()), 0)
+}
+
+// Cancel returns the reserved capacity to the limiter if the call will not happen. Calling Cancel more
+// than once has no effect.
+func (r *Reservation) Cancel() {
+	if !r.ok || r.cancel == nil {
+		return
+	}
+	r.once.Do(r.cancel)
+}
+
+// wait blocks until the reservation r may act or the context is done, canceling r in the latter case.
+func wait(ctx context.Context, clock reuse.Clock, r *Reservation) error {
+	if !r.OK() {
+		return ErrLimitExceeded
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	select {
+	case <-clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// TestTokenBucket verifies burst, refill and reservations ahead of time.
+func TestTokenBucket(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	tb := NewTokenBucket(2, 2)
+	tb.SetClock(fc)
+
+	if !tb.Allow() || !tb.Allow() {
+		t.Fatal("expected burst of 2 to be allowed")
+	}
+	if tb.Allow() {
+		t.Fatal("expected empty bucket")
+	}
+
+	r := tb.Reserve()
+	if !r.OK() || r.Delay() != 500*time.Millisecond {
+		t.Fatalf("expected reservation in 500ms, got %t/%v", r.OK(), r.Delay())
+	}
+	r.Cancel()
+	fc.Advance(500 * time.Millisecond)
+	if !tb.Allow() {
+		t.Fatal("expected canceled reservation and refill to allow a call")
+	}
+	if tb.Allow() {
+		t.Fatal("expected empty bucket")
+	}
+}
+
+// TestTokenBucketWait verifies Wait blocks until a token is available and honors the context.
+func TestTokenBucketWait(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	tb := NewTokenBucket(1, 1)
+	tb.SetClock(fc)
+	_ = tb.Allow()
+
+	done := make(chan error)
+	go func() {
+		done <- tb.Wait(context.Background())
+	}()
+	fc.BlockUntil(1)
+	fc.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tb.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if err := NewTokenBucket(1, 0).Wait(context.Background()); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+// TestSlidingWindow verifies the limit applies to any period of window length.
+func TestSlidingWindow(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	sw := NewSlidingWindow(2, time.Minute)
+	sw.SetClock(fc)
+
+	if !sw.Allow() {
+		t.Fatal("expected first call to be allowed")
+	}
+	fc.Advance(30 * time.Second)
+	if !sw.Allow() {
+		t.Fatal("expected second call to be allowed")
+	}
+	if sw.Allow() {
+		t.Fatal("expected third call within the window to be rejected")
+	}
+
+	r := sw.Reserve()
+	if r.Delay() != 30*time.Second {
+		t.Fatalf("expected reservation in 30s, got %v", r.Delay())
+	}
+	fc.Advance(30 * time.Second)
+	if sw.Allow() {
+		t.Fatal("expected reserved call to take the free slot")
+	}
+	r.Cancel()
+	if !sw.Allow() {
+		t.Fatal("expected canceled reservation to free its slot")
+	}
+}
+
+// TestSlidingWindowCanceledReservations verifies calls allowed after canceling reservations leave the window in time.
+func TestSlidingWindowCanceledReservations(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	sw := NewSlidingWindow(2, time.Second)
+	sw.SetClock(fc)
+
+	sw.Allow()
+	sw.Allow()
+	first, second := sw.Reserve(), sw.Reserve()
+	sw.Reserve()
+	first.Cancel()
+	second.Cancel()
+
+	fc.Advance(1500 * time.Millisecond)
+	if !sw.Allow() {
+		t.Fatal("expected call to be allowed after the window passed")
+	}
+	fc.Advance(time.Second)
+	if !sw.Allow() {
+		t.Fatal("expected call to be allowed with one call in the window")
+	}
+}
+
+// TestKeyed verifies limiters are kept per key and evicted after the idle TTL.
+func TestKeyed(t *testing.T) {
+	fc := reuse.NewFakeClock(time.Now())
+	k := NewKeyed[string](func() Limiter {
+		tb := NewTokenBucket(0.001, 1)
+		tb.SetClock(fc)
+		return tb
+	}, time.Minute)
+	k.SetClock(fc)
+
+	if !k.Allow("a") || !k.Allow("b") {
+		t.Fatal("expected first call per key to be allowed")
+	}
+	if k.Allow("a") {
+		t.Fatal("expected second call for a to be rejected")
+	}
+	if k.Len() != 2 {
+		t.Fatalf("expected 2 limiters, got %d", k.Len())
+	}
+
+	fc.Advance(time.Minute)
+	if !k.Allow("a") {
+		t.Fatal("expected evicted limiter to start fresh")
+	}
+	if k.Len() != 1 {
+		t.Fatalf("expected idle limiter b to be evicted, got %d limiters", k.Len())
+	}
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// SlidingWindow is a Limiter allowing at most limit calls within any period of window length. It keeps
+// the time of each call within the window, so memory grows with limit.
+//
+// A SlidingWindow is safe for concurrent use.
+type SlidingWindow struct {
+
+	// mu protects times and clock.
+	mu sync.Mutex
+
+	// limit is the maximum number of calls within window.
+	limit int
+
+	// window is the length of the sliding window.
+	window time.Duration
+
+	// times holds the times of the calls within the window in ascending order, including reserved future calls.
+	times []time.Time
+
+	// clock provides the current time.
+	clock reuse.Clock
+}
+
+// NewSlidingWindow returns a SlidingWindow allowing limit calls per window.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		limit:  limit,
+		window: window,
+		clock:  reuse.SystemClock{},
+	}
+}
+
+// SetClock replaces the clock used to slide the window, nil restores reuse.SystemClock.
+func (sw *SlidingWindow) SetClock(clock reuse.Clock) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if clock == nil {
+		clock = reuse.SystemClock{}
+	}
+	sw.clock = clock
+}
+
+// Allow reports whether a call may happen now, recording it if so.
+func (sw *SlidingWindow) Allow() bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	now := sw.clock.Now()
+	sw.prune(now)
+	if len(sw.times) >= sw.limit {
+		return false
+	}
+	sw.insert(now)
+	return true
+}
+
+// Wait blocks until a call may happen or the context is done.
+func (sw *SlidingWindow) Wait(ctx context.Context) error {
+	return wait(ctx, sw.currentClock(), sw.Reserve())
+}
+
+// Reserve records a call at the earliest time it may happen and returns that time.
+func (sw *SlidingWindow) Reserve() *Reservation {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.limit <= 0 {
+		return &Reservation{clock: sw.clock}
+	}
+	now := sw.clock.Now()
+	sw.prune(now)
+	timeToAct := now
+	if len(sw.times) >= sw.limit {
+		timeToAct = sw.times[len(sw.times)-sw.limit].Add(sw.window)
+	}
+	sw.insert(timeToAct)
+	return &Reservation{
+		ok:        true,
+		timeToAct: timeToAct,
+		clock:     sw.clock,
+		cancel: func() {
+			sw.mu.Lock()
+			defer sw.mu.Unlock()
+			if i := slices.IndexFunc(sw.times, timeToAct.Equal); i >= 0 {
+				sw.times = slices.Delete(sw.times, i, i+1)
+			}
+		},
+	}
+}
+
+// currentClock returns the clock of the SlidingWindow.
+func (sw *SlidingWindow) currentClock() reuse.Clock {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.clock
+}
+
+// insert records a call at t, keeping times in ascending order after reservations were canceled,
+// sw.mu must be held.
+func (sw *SlidingWindow) insert(t time.Time) {
+	i, _ := slices.BinarySearchFunc(sw.times, t, time.Time.Compare)
+	sw.times = slices.Insert(sw.times, i, t)
+}
+
+// prune removes the calls that left the window, sw.mu must be held.
+func (sw *SlidingWindow) prune(now time.Time) {
+	start := now.Add(-sw.window)
+	i := 0
+	for i < len(sw.times) && !sw.times[i].After(start) {
+		i++
+	}
+	sw.times = slices.Delete(sw.times, 0, i)
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sascha-andres/reuse"
+)
+
+// TokenBucket is a Limiter refilling rate tokens per second up to burst tokens. Each call takes one token.
+//
+// A TokenBucket is safe for concurrent use.
+type TokenBucket struct {
+
+	// mu protects all fields below.
+	mu sync.Mutex
+
+	// rate is the number of tokens added per second.
+	rate float64
+
+	// burst is the maximum number of tokens.
+	burst int
+
+	// tokens is the number of tokens available, negative if reserved ahead.
+	tokens float64
+
+	// last is the time tokens was last updated, zero before the first call.
+	last time.Time
+
+	// clock provides the current time.
+	clock reuse.Clock
+}
+
+// NewTokenBucket returns a full TokenBucket adding rate tokens per second up to burst tokens.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		clock:  reuse.SystemClock{},
+	}
+}
+
+// SetClock replaces the clock used to refill tokens, nil restores reuse.SystemClock.
+func (tb *TokenBucket) SetClock(clock reuse.Clock) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if clock == nil {
+		clock = reuse.SystemClock{}
+	}
+	tb.clock = clock
+}
+
+// Allow reports whether a token is available now, taking it if so.
+func (tb *TokenBucket) Allow() bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.refill(tb.clock.Now())
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or the context is done.
+func (tb *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, tb.currentClock(), tb.Reserve())
+}
+
+// Reserve takes a token, possibly ahead of time, and returns when it becomes available.
+func (tb *TokenBucket) Reserve() *Reservation {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	now := tb.clock.Now()
+	tb.refill(now)
+	if tb.burst <= 0 || (tb.rate <= 0 && tb.tokens < 1) {
+		return &Reservation{clock: tb.clock}
+	}
+	tb.tokens--
+	timeToAct := now
+	if tb.tokens < 0 {
+		timeToAct = now.Add(time.Duration(-tb.tokens / tb.rate * float64(time.Second)))
+	}
+	return &Reservation{
+		ok:        true,
+		timeToAct: timeToAct,
+		clock:     tb.clock,
+		cancel: func() {
+			tb.mu.Lock()
+			defer tb.mu.Unlock()
+			tb.refill(tb.clock.Now())
+			tb.tokens = min(tb.tokens+1, float64(tb.burst))
+		},
+	}
+}
+
+// currentClock returns the clock of the TokenBucket.
+func (tb *TokenBucket) currentClock() reuse.Clock {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.clock
+}
+
+// refill adds the tokens accumulated since the last update, tb.mu must be held.
+func (tb *TokenBucket) refill(now time.Time) {
+	if !tb.last.IsZero() && now.After(tb.last) {
+		tb.tokens = min(tb.tokens+now.Sub(tb.last).Seconds()*tb.rate, float64(tb.burst))
+	}
+	if tb.last.IsZero() || now.After(tb.last) {
+		tb.last = now
+	}
+}
@@ -0,0 +1,81 @@
+package reuse
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfterError is implemented by errors carrying a hint how long to wait before the next attempt,
+// e.g. from a Retry-After header. A RetryPolicy waits the hinted delay instead of its own schedule,
+// capped by MaxDelay.
+type RetryAfterError interface {
+	error
+
+	// RetryAfter returns the delay to wait before the next attempt.
+	RetryAfter() time.Duration
+}
+
+// retryAfterError is the RetryAfterError returned by RetryAfter.
+type retryAfterError struct {
+
+	// err is the wrapped error.
+	err error
+
+	// after is the hinted delay.
+	after time.Duration
+}
+
+// Error returns the message of the wrapped error
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter returns the hinted delay
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.after
+}
+
+// RetryAfter wraps err with a hint to wait d before the next attempt. RetryAfter(nil, d) returns nil.
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, after: d}
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, given either as delay in seconds or
+// as HTTP date relative to now. It returns false if the value is empty or invalid. Dates in the
+// past result in a zero delay.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
+
+// RetryAfterFromResponse returns the delay requested by the Retry-After header of resp.
+// It returns false if resp is nil or carries no valid header.
+func RetryAfterFromResponse(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	return ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+}
@@ -0,0 +1,76 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestParseRetryAfter verifies parsing of delay seconds and HTTP dates.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			d, ok := ParseRetryAfter(test.value, now)
+			if ok != test.ok || d != test.expected {
+				t.Fatalf("expected %v/%t, got %v/%t", test.expected, test.ok, d, ok)
+			}
+		})
+	}
+}
+
+// TestRetryPolicyRetryAfter verifies a hinted delay replaces the schedule and is capped by MaxDelay.
+func TestRetryPolicyRetryAfter(t *testing.T) {
+	var seen []time.Duration
+	p := &RetryPolicy{
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		MaxAttempts: 3,
+		OnRetry: func(_ int, _ error, delay time.Duration) {
+			seen = append(seen, delay)
+		},
+	}
+	calls := 0
+	_ = p.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return RetryAfter(errors.New("throttled"), 2*time.Millisecond)
+		}
+		return RetryAfter(errors.New("throttled"), time.Hour)
+	})
+	if len(seen) != 2 || seen[0] != 2*time.Millisecond || seen[1] != 5*time.Millisecond {
+		t.Fatalf("expected delays [2ms 5ms], got %v", seen)
+	}
+}
+
+// TestRetryPolicyRetryAfterSchedule verifies a hinted delay is capped by the largest scheduled delay without MaxDelay.
+func TestRetryPolicyRetryAfterSchedule(t *testing.T) {
+	var seen []time.Duration
+	p := &RetryPolicy{
+		Delays: []time.Duration{time.Millisecond, 3 * time.Millisecond},
+		OnRetry: func(_ int, _ error, delay time.Duration) {
+			seen = append(seen, delay)
+		},
+	}
+	_ = p.Do(context.Background(), func(context.Context) error {
+		return RetryAfter(errors.New("throttled"), 24*time.Hour)
+	})
+	if len(seen) != 1 || seen[0] != 3*time.Millisecond {
+		t.Fatalf("expected delays [3ms], got %v", seen)
+	}
+}
@@ -0,0 +1,125 @@
+package reuse
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is matched by errors.Is when a RetryPolicy stops because its RetryBudget is exhausted
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// retryBudgetSlots is the number of slots a RetryBudget window is divided into.
+const retryBudgetSlots = 10
+
+// retryBudgetSlot counts deposits and withdrawals within one slot of the window.
+type retryBudgetSlot struct {
+
+	// index is the absolute slot number, used to detect stale slots.
+	index int64
+
+	// deposits is the number of successful requests.
+	deposits int
+
+	// withdrawals is the number of retries.
+	withdrawals int
+}
+
+// RetryBudget limits retries across all callers sharing it to a ratio of the successful requests
+// within a sliding window, plus a minimum number of retries always allowed per window. It prevents
+// retries from multiplying the load on a failing dependency.
+//
+// A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+
+	// mu protects slots.
+	mu sync.Mutex
+
+	// ratio is the number of retries allowed per successful request.
+	ratio float64
+
+	// minRetries is the number of retries allowed per window regardless of successful requests.
+	minRetries int
+
+	// slotDuration is the duration covered by a single slot.
+	slotDuration time.Duration
+
+	// slots holds the counts of the current window.
+	slots [retryBudgetSlots]retryBudgetSlot
+
+	// clock provides the current time for the sliding window.
+	clock Clock
+}
+
+// NewRetryBudget returns a RetryBudget allowing ratio retries per successful request and at least
+// minRetries retries within window. NewRetryBudget(0.2, 10, 10*time.Second) allows retries for 20%
+// of the successful requests of the last ten seconds, but at least 10.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	return &RetryBudget{
+		ratio:        ratio,
+		minRetries:   minRetries,
+		slotDuration: max(window/retryBudgetSlots, 1),
+		clock:        SystemClock{},
+	}
+}
+
+// SetClock replaces the clock used for the sliding window, nil restores SystemClock.
+func (b *RetryBudget) SetClock(clock Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clockOrSystem(clock)
+}
+
+// Deposit records a successful request.
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current().deposits++
+}
+
+// TryWithdraw reserves a retry and reports whether the budget allowed it.
+func (b *RetryBudget) TryWithdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.balanceLocked() < 1 {
+		return false
+	}
+	b.current().withdrawals++
+	return true
+}
+
+// Balance returns the number of retries currently available.
+func (b *RetryBudget) Balance() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int(b.balanceLocked())
+}
+
+// balanceLocked sums up the slots of the current window, b.mu must be held.
+func (b *RetryBudget) balanceLocked() float64 {
+	index := b.index()
+	deposits, withdrawals := 0, 0
+	for _, slot := range b.slots {
+		if index-slot.index >= retryBudgetSlots {
+			continue
+		}
+		deposits += slot.deposits
+		withdrawals += slot.withdrawals
+	}
+	return float64(b.minRetries) + b.ratio*float64(deposits) - float64(withdrawals)
+}
+
+// current returns the slot for the current time, resetting it if stale, b.mu must be held.
+func (b *RetryBudget) current() *retryBudgetSlot {
+	index := b.index()
+	slot := &b.slots[index%retryBudgetSlots]
+	if slot.index != index {
+		*slot = retryBudgetSlot{index: index}
+	}
+	return slot
+}
+
+// index returns the absolute slot number for the current time.
+func (b *RetryBudget) index() int64 {
+	return b.clock.Now().UnixNano() / int64(b.slotDuration)
+}
@@ -0,0 +1,59 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetryBudget verifies the floor, deposits and expiry of a RetryBudget.
+func TestRetryBudget(t *testing.T) {
+	fc := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewRetryBudget(0.5, 1, 10*time.Second)
+	b.SetClock(fc)
+
+	if !b.TryWithdraw() {
+		t.Fatal("expected floor to allow a retry")
+	}
+	if b.TryWithdraw() {
+		t.Fatal("expected budget to be exhausted")
+	}
+
+	b.Deposit()
+	b.Deposit()
+	if b.Balance() != 1 {
+		t.Fatalf("expected balance 1, got %d", b.Balance())
+	}
+	if !b.TryWithdraw() {
+		t.Fatal("expected deposits to allow a retry")
+	}
+	if b.TryWithdraw() {
+		t.Fatal("expected budget to be exhausted")
+	}
+
+	fc.Advance(10 * time.Second)
+	if b.Balance() != 1 {
+		t.Fatalf("expected balance to return to floor, got %d", b.Balance())
+	}
+}
+
+// TestRetryPolicyBudget verifies a policy fails fast once its budget is exhausted.
+func TestRetryPolicyBudget(t *testing.T) {
+	failing := errors.New("failing")
+	p := &RetryPolicy{BaseDelay: time.Millisecond, MaxAttempts: 5, Budget: NewRetryBudget(0, 1, time.Minute)}
+	calls := 0
+	err := p.Do(context.Background(), func(context.Context) error {
+		calls++
+		return failing
+	})
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if !errors.Is(err, failing) {
+		t.Fatalf("expected %v to wrap %v", err, failing)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,73 @@
+package reuse
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRetriesExhausted is matched by errors.Is for every RetryError
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// RetryError is returned when a RetryPolicy gives up without a successful attempt.
+type RetryError struct {
+
+	// Attempts is the number of calls made to the request.
+	Attempts int
+
+	// Elapsed is the time passed between the first attempt and giving up.
+	Elapsed time.Duration
+
+	// Last is the error returned by the final attempt.
+	Last error
+
+	// Errors holds the errors of all attempts in order if RetryPolicy.CollectErrors is set.
+	Errors MultiError
+}
+
+// Error returns the attempt count together with the last error
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Last)
+}
+
+// Unwrap returns the error of the final attempt
+func (e *RetryError) Unwrap() error {
+	return e.Last
+}
+
+// Is reports whether target is ErrRetriesExhausted
+func (e *RetryError) Is(target error) bool {
+	return target == ErrRetriesExhausted
+}
+
+// PermanentError marks an error that must not be retried.
+type PermanentError struct {
+
+	// Err is the wrapped error returned to the caller of the retry.
+	Err error
+}
+
+// Error returns the message of the wrapped error
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that retrying stops immediately and err is returned.
+// Permanent(nil) returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err or any error in its chain is a PermanentError.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,20 @@
+package reuse
+
+import (
+	"log/slog"
+	"time"
+)
+
+// SlogOnRetry returns a RetryPolicy.OnRetry hook logging each retry as warning with the given message.
+func SlogOnRetry(logger *slog.Logger, msg string) func(attempt int, err error, delay time.Duration) {
+	return func(attempt int, err error, delay time.Duration) {
+		logger.Warn(msg, "err", err, "attempt", attempt, "delay", delay)
+	}
+}
+
+// SlogOnGiveUp returns a RetryPolicy.OnGiveUp hook logging the final failure as error with the given message.
+func SlogOnGiveUp(logger *slog.Logger, msg string) func(attempts int, err error) {
+	return func(attempts int, err error) {
+		logger.Error(msg, "err", err, "attempts", attempts)
+	}
+}
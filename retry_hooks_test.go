@@ -0,0 +1,47 @@
+package reuse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSlogHooks verifies the slog hooks log the attempt, delay and error of retries and the final failure.
+func TestSlogHooks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	p := &RetryPolicy{
+		BaseDelay:   time.Millisecond,
+		MaxAttempts: 2,
+		OnRetry:     SlogOnRetry(logger, "retrying"),
+		OnGiveUp:    SlogOnGiveUp(logger, "giving up"),
+	}
+	_ = p.Do(context.Background(), func(context.Context) error {
+		return errors.New("failing")
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	expected := []string{
+		`level=WARN msg=retrying err=failing attempt=1 delay=1ms`,
+		`level=ERROR msg="giving up" err="failed after 2 attempts: failing" attempts=2`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d log lines, got %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], line)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package reuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// DefaultRetryStatusCodes are the status codes retried by a RetryTransport without RetryStatusCodes.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// idempotentMethods are the HTTP methods a RetryTransport retries.
+var idempotentMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete,
+}
+
+// RetryTransport is a http.RoundTripper retrying idempotent requests on transport errors and
+// retryable status codes using a RetryPolicy. Requests with an Idempotency-Key header are treated
+// as idempotent regardless of their method. Retry-After headers are honored as described by
+// RetryAfterError.
+//
+// Requests with a body are only retried if GetBody is set, as done by http.NewRequest for common
+// body types. When the policy gives up on a retryable status code, the last response is returned.
+type RetryTransport struct {
+
+	// Base executes the single attempts, nil means http.DefaultTransport.
+	Base http.RoundTripper
+
+	// Policy describes the retries, nil means DefaultRetryPolicy.
+	Policy *RetryPolicy
+
+	// RetryStatusCodes are the status codes to retry, nil means DefaultRetryStatusCodes.
+	RetryStatusCodes []int
+}
+
+// statusError signals a response with a retryable status code to the retry loop.
+type statusError struct {
+
+	// resp is the response received.
+	resp *http.Response
+}
+
+// Error returns the status of the response
+func (e *statusError) Error() string {
+	return fmt.Sprintf("retryable status %s", e.resp.Status)
+}
+
+// RoundTrip executes the request, retrying it if it is idempotent and replayable.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !isRetryableRequest(req) {
+		return base.RoundTrip(req)
+	}
+	policy := t.Policy
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	statusCodes := t.RetryStatusCodes
+	if statusCodes == nil {
+		statusCodes = DefaultRetryStatusCodes
+	}
+	clock := clockOrSystem(policy.Clock)
+
+	var previous *http.Response
+	attempt := 0
+	resp, err := DoValueWithPolicy(req.Context(), policy, func(ctx context.Context) (*http.Response, error) {
+		attempt++
+		if previous != nil {
+			drainAndClose(previous.Body)
+			previous = nil
+		}
+		r := req
+		if attempt > 1 {
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, Permanent(err)
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := base.RoundTrip(r)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, Permanent(err)
+			}
+			return nil, err
+		}
+		if !slices.Contains(statusCodes, resp.StatusCode) {
+			return resp, nil
+		}
+		previous = resp
+		var statusErr error = &statusError{resp: resp}
+		if d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), clock.Now()); ok {
+			statusErr = RetryAfter(statusErr, d)
+		}
+		return nil, statusErr
+	})
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.resp == previous {
+		return statusErr.resp, nil
+	}
+	if err != nil && previous != nil {
+		drainAndClose(previous.Body)
+	}
+	return resp, err
+}
+
+// isRetryableRequest reports whether req is idempotent and its body can be replayed.
+func isRetryableRequest(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	return slices.Contains(idempotentMethods, method)
+}
+
+// drainAndClose reads the remainder of a response body so the connection can be reused and closes it.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4096))
+	DiscardError(body.Close)
+}
@@ -0,0 +1,112 @@
+package reuse
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPolicy returns a fast RetryPolicy for transport tests.
+func testPolicy() *RetryPolicy {
+	return &RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxAttempts: 3}
+}
+
+// TestRetryTransportStatus verifies retryable status codes are retried and bodies are replayed.
+func TestRetryTransportStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("expected body 'payload', got %q", body)
+		}
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{Policy: testPolicy()}}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer DiscardError(resp.Body.Close)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+// TestRetryTransportGiveUp verifies the last response is returned once the policy gives up.
+func TestRetryTransportGiveUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{Policy: testPolicy()}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer DiscardError(resp.Body.Close)
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+// TestRetryTransportNonIdempotent verifies POST requests are not retried.
+func TestRetryTransportNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{Policy: testPolicy()}}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	DiscardError(resp.Body.Close)
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call, got %d", calls.Load())
+	}
+}
+
+// TestRetryTransportConnectionError verifies transport errors are retried and returned when the policy gives up.
+func TestRetryTransportConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	var retries atomic.Int32
+	policy := testPolicy()
+	policy.OnRetry = func(int, error, time.Duration) {
+		retries.Add(1)
+	}
+	client := &http.Client{Transport: &RetryTransport{Policy: policy}}
+	_, err := client.Get(url)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if retries.Load() != 2 {
+		t.Fatalf("expected 2 retries, got %d", retries.Load())
+	}
+}